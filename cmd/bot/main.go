@@ -1,19 +1,30 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/SNKT2024/linkedin-automation/internal/browser"
 	"github.com/SNKT2024/linkedin-automation/internal/config"
 	"github.com/SNKT2024/linkedin-automation/internal/guard"
 	"github.com/SNKT2024/linkedin-automation/internal/linkedin"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/session"
 	"github.com/SNKT2024/linkedin-automation/internal/stealth"
 	"github.com/SNKT2024/linkedin-automation/internal/storage"
 	"github.com/go-rod/rod"
@@ -25,300 +36,1122 @@ func main() {
 	// ==========================================
 	// CONFIGURATION LOADING
 	// ==========================================
-	log.Println("Loading configuration from .env...")
+	// Until the configured logger exists, records go to the default text logger
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("❌ Failed to load configuration: %v", err)
+		fatal("❌ Failed to load configuration", logging.Err(err))
 	}
-	log.Println("✅ Configuration loaded successfully")
-	log.Printf("   Email: %s", cfg.Email)
-	log.Printf("   Search Keyword: %s", cfg.SearchKeyword)
-	log.Printf("   Daily Invite Limit: %d", cfg.InviteLimit)
-	log.Printf("   Daily Search Limit: %d", cfg.SearchLimit)
-	log.Printf("   Working Hours: %s - %s", cfg.WorkStart, cfg.WorkEnd)
+	storage.ConfigureCanonical(cfg.Endpoints.BaseURL, cfg.Endpoints.ProfilePath)
 
 	// ==========================================
 	// COMMAND-LINE FLAGS
 	// ==========================================
-	mode := flag.String("mode", cfg.DefaultMode, "Execution mode: search, connect, demo, login, message")
+	mode := flag.String("mode", cfg.DefaultMode, "Execution mode: search, connect, demo, login, message, inbox-sync, daemon, doctor, migrate, dedupe, suppress, campaigns, history, config-check, secrets, session-status, breaker")
+	snapshotDir := flag.String("snapshots", "", "Doctor mode: directory of saved .html pages to check instead of the live site")
+	doctorURLs := flag.String("urls", "", "Doctor mode: comma-separated live URLs to check after login")
+	reportFile := flag.String("report", "", "Doctor mode: write the JSON report to this file instead of stdout")
+	profileURL := flag.String("profile", "", "History mode: profile URL whose received notes and messages to show")
+	campaign := flag.String("campaign", cfg.Campaign, "Campaign whose queries, templates, limits and schedule search/connect/message use")
+	flag.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "Walk every flow and log what would be sent, without clicking Connect/Send or changing profile status")
+	flag.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "Log level: debug, info, warn or error")
+	flag.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "Log format: text or json")
 	flag.Parse()
 
-	log.Printf("\n🎯 Execution Mode: %s\n", *mode)
+	// ==========================================
+	// LOGGING
+	// ==========================================
+	// Every record of this run carries its run ID, mode and campaign
+	runID := newRunID()
+	storage.SetRunID(runID)
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fatal("❌ Invalid logging flags", logging.Err(err))
+	}
+	useLogger(logger.With(logging.KeyRunID, runID, logging.KeyMode, strings.ToLower(*mode), logging.KeyCampaign, *campaign))
+
+	slog.Info("✅ Configuration loaded",
+		"email", cfg.Email,
+		"base_url", cfg.Endpoints.BaseURL,
+		"selectors", fmt.Sprintf("%s v%d", cfg.Selectors.Name(), cfg.Selectors.Version()),
+		"config_file", cfg.ConfigFile)
+	if cfg.Secrets != nil {
+		slog.Info("🔐 Secrets file in use", "file", cfg.SecretsFile, "password_source", cfg.Sources["password"])
+		if cfg.Sources["password"] != "secrets file "+cfg.SecretsFile {
+			slog.Warn("⚠️ The password is still set in plain text. Run -mode=secrets import, then remove it.")
+		}
+	}
+	if cfg.DryRun {
+		slog.Info("🧪 DRY RUN: nothing will be sent and no profile status will change")
+	}
+
+	// Database maintenance runs without working hours, browser or login.
+	// "-mode=migrate" applies pending migrations; "-mode=migrate status" only reports.
+	if strings.ToLower(*mode) == "migrate" {
+		runMigrateMode(flag.Arg(0) == "status")
+		return
+	}
+	if strings.ToLower(*mode) == "dedupe" {
+		runDedupeMode()
+		return
+	}
+	// "-mode=suppress list.csv" imports a do-not-contact list; without a file it prints the list.
+	if strings.ToLower(*mode) == "suppress" {
+		runSuppressMode(flag.Arg(0))
+		return
+	}
+	if strings.ToLower(*mode) == "campaigns" {
+		runCampaignsMode(cfg)
+		return
+	}
+	if strings.ToLower(*mode) == "history" {
+		runHistoryMode(*profileURL)
+		return
+	}
+	// "-mode=secrets import" moves the password and cookies into SECRETS_FILE; without it, reports what is stored.
+	if strings.ToLower(*mode) == "secrets" {
+		runSecretsMode(cfg, flag.Arg(0) == "import")
+		return
+	}
+
+	if strings.ToLower(*mode) == "session-status" {
+		runSessionStatusMode(cfg)
+		return
+	}
+	// "-mode=breaker reset" clears a tripped circuit breaker; without it, reports the breaker's state.
+	if strings.ToLower(*mode) == "breaker" {
+		runBreakerMode(flag.Arg(0) == "reset")
+		return
+	}
+
+	// Everything below acts on one campaign
+	if err := cfg.UseCampaign(*campaign); err != nil {
+		fatal("❌ Failed to select campaign", logging.Err(err))
+	}
+	slog.Info("📣 Campaign selected",
+		"queries", cfg.SearchQueries,
+		"invite_limit", formatCap(cfg.CampaignInviteLimit),
+		"search_limit", formatCap(cfg.CampaignSearchLimit),
+		"schedule", cfg.Schedule.String())
+
+	// Print the resolved configuration (campaign applied) without touching anything
+	if strings.ToLower(*mode) == "config-check" {
+		runConfigCheckMode(cfg)
+		return
+	}
+
+	// Offline doctor only needs a browser to render the snapshots:
+	// no working hours, database or login.
+	if strings.ToLower(*mode) == "doctor" && *snapshotDir != "" {
+		runDoctorSnapshots(cfg, *snapshotDir, *reportFile)
+		return
+	}
+
+	// Daemon mode checks working hours itself: it sleeps until the next window instead of exiting
+	if strings.ToLower(*mode) == "daemon" {
+		runDaemonMode(cfg, runID)
+		return
+	}
 
 	// ==========================================
 	// SAFETY CHECKS
 	// ==========================================
-	log.Println("==========================================")
-	log.Println("Performing Safety Checks...")
-	log.Println("==========================================")
+	slog.Info("Performing safety checks")
 
 	// 1. Check working hours
-	if err := guard.CheckWorkingHours(cfg); err != nil {
-		log.Printf("⚠️ SAFETY STOP: %v", err)
-		log.Println("The bot will not run outside of configured working hours.")
-		os.Exit(1)
+	if err := cfg.Schedule.Check(time.Now()); err != nil {
+		fatal("⚠️ SAFETY STOP: the bot will not run outside of configured working hours", logging.Err(err))
 	}
-	log.Println("✅ Working hours check passed")
+	slog.Info("✅ Working hours check passed")
 
 	// ==========================================
 	// DATABASE INITIALIZATION
 	// ==========================================
-	log.Println("\nInitializing database...")
 	db, err := storage.InitDB()
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize database: %v", err)
+		fatal("❌ Failed to initialize database", logging.Err(err))
 	}
 	defer storage.CloseDB(db)
-	log.Println("✅ Database initialized successfully")
+
+	if _, err := storage.UseCampaign(db, cfg.Campaign); err != nil {
+		fatal("❌ Failed to select campaign", logging.Err(err))
+	}
+	budget := guard.NewBudget(db, cfg.Budgets(), cfg.Schedule.Location())
+
+	// 2. Refuse to start while the circuit breaker is tripped (it persists across runs)
+	breaker := guard.NewBreaker(db, cfg.BreakerWait, cfg.BreakerMaxFailures)
+	if err := breaker.Check(); err != nil {
+		fatal("🚨 SAFETY STOP: the circuit breaker is tripped. Check the account, then wait or run -mode=breaker reset", logging.Err(err))
+	}
+	guard.SetBreaker(breaker)
+	defer guard.SetBreaker(nil)
+	slog.Info("✅ Circuit breaker check passed")
 
 	// ==========================================
 	// BROWSER INITIALIZATION
 	// ==========================================
-	log.Println("\n==========================================")
-	log.Println("Initializing Browser...")
-	log.Println("==========================================")
-
-	b, err := browser.NewBrowser()
+	b, err := browser.NewBrowser(cfg.Headless)
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize browser: %v", err)
+		fatal("❌ Failed to initialize browser", logging.Err(err))
 	}
 	defer b.MustClose()
 
 	page, err := browser.NewStealthPage(b)
 	if err != nil {
-		log.Fatalf("❌ Failed to create stealth page: %v", err)
+		fatal("❌ Failed to create stealth page", logging.Err(err))
 	}
-	log.Println("✅ Browser & Stealth Page Ready")
+	slog.Info("✅ Browser & Stealth Page Ready")
 
 	// ==========================================
 	// LINKEDIN AUTHENTICATION
 	// ==========================================
-	log.Println("\n==========================================")
-	log.Println("Authenticating with LinkedIn...")
-	log.Println("==========================================")
-
-	if err := linkedin.Login(b, page, cfg); err != nil {
-		log.Fatalf("❌ LinkedIn login failed: %v", err)
+	slog.Info("Authenticating with LinkedIn")
+	if err := linkedin.Login(b, page, db, cfg); err != nil {
+		fatal("❌ LinkedIn login failed", logging.Err(err))
 	}
-	log.Println("✅ Successfully logged into LinkedIn")
+	slog.Info("✅ Successfully logged into LinkedIn")
 
 	// ==========================================
 	// MODE EXECUTION
 	// ==========================================
-	log.Println("\n==========================================")
-	log.Printf("Executing Mode: %s", strings.ToUpper(*mode))
-	log.Println("==========================================\n")
+	slog.Info("🎯 Executing mode")
 
 	switch strings.ToLower(*mode) {
 	case "search":
-		runSearchMode(page, db, cfg)
+		runSearchMode(page, db, cfg, budget)
 
 	case "connect":
-		runConnectMode(page, db, cfg)
+		runConnectMode(page, db, cfg, budget)
 
 	case "demo":
-		runDemoMode(page, db, cfg)
+		runDemoMode(page, db, cfg, budget)
 
 	case "login":
-		log.Println("🔵 Login Mode: Keeping browser open for manual inspection.")
+		slog.Info("🔵 Login Mode: Keeping browser open for manual inspection")
 		for i := 2; i > 0; i-- {
-			log.Printf("   Time remaining: %d minute(s)...", i)
+			slog.Info("Time remaining", "minutes", i)
 			time.Sleep(1 * time.Minute)
 		}
 
 	case "message":
-		runMessageMode(page,db,cfg)
+		runMessageMode(page, db, cfg, budget)
+
+	case "inbox-sync":
+		runInboxSyncMode(page, db, cfg)
+
+	case "doctor":
+		runDoctorLive(page, cfg, *doctorURLs, *reportFile)
 
 	default:
-		log.Fatalf("❌ Invalid mode: %s", *mode)
+		fatal("❌ Invalid mode")
 	}
 
 	// ==========================================
 	// FINAL STATISTICS
 	// ==========================================
-	showFinalStatistics(db, cfg)
+	showFinalStatistics(db, cfg, budget)
+	if cfg.DryRun {
+		printDryRunResults(db, runID)
+	}
 
 	fmt.Println("\n✅ Execution complete. Press Enter to exit...")
 	fmt.Scanln()
 }
 
-// runSearchMode executes the search workflow with rate limiting
-func runSearchMode(page *rod.Page, db *sql.DB, cfg *config.Config) {
-	log.Println("🔍 Starting Search Mode...")
+// fatal logs msg at error level and exits, like log.Fatal
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
 
-	// 1. RATE LIMIT CHECK
-	todayCount, err := guard.GetTodayCount(db)
-	if err != nil {
-		log.Printf("⚠️ Error checking search limits: %v", err)
-		return
+// stopped reports whether err means the run has to stop before its next action (see guard.Proceed), and logs why
+func stopped(err error) bool {
+	if !errors.Is(err, guard.ErrStopped) {
+		return false
 	}
+	slog.Info("🛑 Stopping before the next action", logging.Err(err))
+	return true
+}
 
-	log.Printf("📊 Search Limit Status: %d/%d profiles collected today", todayCount, cfg.SearchLimit)
+// overBudget reports whether err means a budget ceiling was reached (see guard.Budget.Reserve), and logs which
+func overBudget(err error) bool {
+	if !errors.Is(err, guard.ErrBudgetExhausted) {
+		return false
+	}
+	slog.Info("🛑 Budget used up", logging.Err(err))
+	return true
+}
 
-	if todayCount >= cfg.SearchLimit {
-		log.Println("🛑 Daily search limit reached. Skipping search execution.")
-		return
+// updateStatus moves the profile to status, logging a refused transition (see storage.ErrInvalidTransition) or a failed write
+func updateStatus(plog *slog.Logger, db *sql.DB, profileURL string, status storage.ProfileStatus, reason string) {
+	if err := storage.UpdateStatus(db, profileURL, status, reason); err != nil {
+		plog.Error("❌ Failed to update profile status", "status", status, logging.Err(err))
 	}
+}
 
-	// Calculate allowable pages (optional optimization)
-	// We run the search anyway, relying on the loop to stop or just run max pages 
-	// since we want to fill the buffer.
-	
-	newProfiles, err := linkedin.SearchPeople(page, db, cfg.SearchKeyword, cfg.MaxPages)
-	if err != nil {
-		log.Printf("❌ Search failed: %v", err)
-		return
+// useLogger makes logger the default and hands it to every package that logs
+func useLogger(logger *slog.Logger) {
+	slog.SetDefault(logger)
+	storage.SetLogger(logger)
+	linkedin.SetLogger(logger)
+	guard.SetLogger(logger)
+	browser.SetLogger(logger)
+	session.SetLogger(logger)
+}
+
+// newRunID returns an identifier for this execution, recorded with every profile event
+func newRunID() string {
+	return fmt.Sprintf("%s-%04x", time.Now().Format("20060102-150405"), rand.Intn(0x10000))
+}
+
+// runSearchMode executes the search workflow within the budget, once per campaign query
+func runSearchMode(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget) {
+	slog.Info("🔍 Starting Search Mode")
+
+	total := 0
+	for _, query := range cfg.SearchQueries {
+		if err := guard.Proceed(); stopped(err) {
+			break
+		}
+
+		// 1. BUDGET CHECK (before every query, earlier ones count too)
+		remaining, err := budget.Remaining(storage.ActionSearchPage)
+		if err != nil {
+			slog.Warn("⚠️ Error checking search budget", logging.Err(err))
+			return
+		}
+
+		slog.Info("📊 Search Budget Status", "profiles_remaining", remaining)
+
+		if remaining <= 0 {
+			slog.Info("🛑 Search budget used up. Skipping search execution")
+			break
+		}
+
+		// SearchPeople checks the budget again before every results page,
+		// so a query stops as soon as the collected profiles fill it.
+
+		slog.Info("🔎 Query", "query", query)
+		newProfiles, err := linkedin.SearchPeople(page, db, cfg, budget, query, cfg.MaxPages)
+		total += len(newProfiles)
+		if stopped(err) || overBudget(err) {
+			break
+		}
+		if err != nil {
+			slog.Error("❌ Search failed", "query", query, logging.Err(err))
+			if err := guard.Failure(err); stopped(err) {
+				break
+			}
+			continue
+		}
+		guard.Success()
 	}
 
-	log.Printf("\n✅ Search Complete. Found %d NEW profiles.", len(newProfiles))
+	slog.Info("✅ Search Complete", "new_profiles", total)
 }
 
-// runConnectMode executes the connection workflow with strict rate limiting & personalization
-func runConnectMode(page *rod.Page, db *sql.DB, cfg *config.Config) {
-	log.Println("🤝 Starting Connect Mode...")
+// runConnectMode executes the connection workflow within the budget & with personalization
+func runConnectMode(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget) {
+	slog.Info("🤝 Starting Connect Mode")
 
-	// 1. RATE LIMIT CHECK
-	inviteCount, err := guard.GetDailyInviteCount(db)
+	// 1. BUDGET CHECK (hourly, daily and 7-day invite ceilings)
+	remaining, err := budget.Remaining(storage.ActionInvite)
 	if err != nil {
-		log.Printf("⚠️ Error checking invite limits: %v", err)
+		slog.Warn("⚠️ Error checking invite budget", logging.Err(err))
 		return
 	}
 
-	remaining := cfg.InviteLimit - inviteCount
-	log.Printf("📊 Invite Limit Status: %d/%d sent today (Remaining: %d)", inviteCount, cfg.InviteLimit, remaining)
+	slog.Info("📊 Invite Budget Status", "remaining", remaining)
 
 	if remaining <= 0 {
-		log.Println("🛑 Daily invite limit reached. Stopping Connect Mode.")
-		return
+		if !cfg.DryRun {
+			slog.Info("🛑 Invite budget used up. Stopping Connect Mode")
+			return
+		}
+		// Dry runs send nothing, so preview a full day's batch even when the budget is used up
+		remaining = cfg.InviteLimit
+		if cfg.CampaignInviteLimit != nil {
+			remaining = min(remaining, *cfg.CampaignInviteLimit)
+		}
 	}
 
 	// 2. Fetch profiles
-	log.Printf("Fetching up to %d profiles to invite...", remaining)
+	slog.Debug("Fetching profiles to invite", "limit", remaining)
 	profiles, err := storage.GetProfilesToInvite(db, remaining)
 	if err != nil {
-		log.Printf("❌ Failed to fetch profiles: %v", err)
+		slog.Error("❌ Failed to fetch profiles", logging.Err(err))
 		return
 	}
 
 	if len(profiles) == 0 {
-		log.Println("⚠️ No profiles available for connection (Run 'search' mode first)")
+		slog.Info("⚠️ No profiles available for connection (Run 'search' mode first)")
 		return
 	}
 
-	log.Printf("Found %d profiles ready for connection", len(profiles))
+	slog.Info("Found profiles ready for connection", "count", len(profiles))
 
 	// 3. Process Connections
 	var successCount = 0
+	var dryRunCount = 0
 
-	for i, profileURL := range profiles {
-		log.Printf("\n========== Profile %d/%d ==========", i+1, len(profiles))
-		
-		// Navigate first to get the name
-		page.MustNavigate(profileURL)
-		page.MustWaitLoad()
-		stealth.RandomSleep(3000, 5000)
+	for i, profile := range profiles {
+		if err := guard.Proceed(); stopped(err) {
+			break
+		}
+		profileURL := profile.URL
+		plog := slog.With(logging.Profile(profileURL))
+		plog.Info("👉 Connecting", "index", i+1, "total", len(profiles))
+
+		// Attempt to connect. The note is personalized inside from the stored profile,
+		// whose name/headline are refreshed when the page is visited.
+		status, connErr := linkedin.ConnectWithProfile(page, db, cfg, budget, profile, cfg.ConnectTemplate)
 
-		// Extract First Name for Personalization
-		firstName := "there" // Default fallback
-		if nameEl, err := page.Timeout(2 * time.Second).Element("h1"); err == nil {
-			text := nameEl.MustText()
-			parts := strings.Split(text, " ")
-			if len(parts) > 0 {
-				firstName = parts[0]
+		// The budget or the circuit breaker stopped the run: nothing more is sent
+		if status == "stopped" {
+			if !stopped(connErr) && !overBudget(connErr) {
+				plog.Error("❌ Safety check failed", logging.Err(connErr))
 			}
+			break
 		}
 
-		// Create Personalized Message
-		message := strings.ReplaceAll(cfg.ConnectMessageTemplate,"{firstName}",firstName)
+		// Dry runs record the outcome instead of saving it, leaving status and the budget untouched
+		if cfg.DryRun && status != "dry_run" {
+			reason := ""
+			if connErr != nil {
+				reason = connErr.Error()
+			}
+			plog.Info("🧪 [DRY RUN] Outcome not saved", "outcome", status)
+			if err := storage.RecordDryRun(db, profileURL, "connect", status, reason); err != nil {
+				plog.Warn("⚠️ Failed to record dry-run result", logging.Err(err))
+			}
+			status = "dry_run"
+		}
 
-		// Attempt to connect (Passing the message now!)
-		status, connErr := linkedin.ConnectWithProfile(page, profileURL, message)
+		// Unexplained failures in a row trip the circuit breaker; any other outcome resets the count
+		tripped := false
+		if status == "failed" {
+			tripped = stopped(guard.Failure(connErr))
+		} else {
+			guard.Success()
+		}
 
 		// Update Database based on result
 		switch status {
 		case "clicked":
-			log.Println("✅ Connection request sent")
+			plog.Info("✅ Connection request sent")
 			successCount++
-			storage.UpdateStatus(db, profileURL, "invited")
+			updateStatus(plog, db, profileURL, storage.StatusInvited, "connect: invite sent")
 
 			// === ☕ NEW: COFFEE BREAK LOGIC ===
-            // After every 3 successful invites, take a long break (1-3 minutes)
-            if successCount > 0 && successCount%3 == 0 {
-                breakTime := 60000 + rand.Intn(120000) // 60s - 180s
-                log.Printf("☕ Taking a coffee break for %d seconds (Stealth Protocol)...", breakTime/1000)
-                time.Sleep(time.Duration(breakTime) * time.Millisecond)
-                continue // Skip the normal safety delay since we just took a long break
-            }
-            // ==================================
+			// After every 3 successful invites, take a long break (1-3 minutes)
+			if successCount > 0 && successCount%3 == 0 {
+				breakTime := 60000 + rand.Intn(120000) // 60s - 180s
+				slog.Info("☕ Taking a coffee break (Stealth Protocol)", "seconds", breakTime/1000)
+				time.Sleep(time.Duration(breakTime) * time.Millisecond)
+				continue // Skip the normal safety delay since we just took a long break
+			}
+			// ==================================
 		case "skipped_pending":
-			storage.UpdateStatus(db, profileURL, "pending")
+			updateStatus(plog, db, profileURL, storage.StatusPending, "connect: invite already pending")
 		case "skipped_connected":
-			storage.UpdateStatus(db, profileURL, "already_connected")
+			updateStatus(plog, db, profileURL, storage.StatusAlreadyConnected, "connect: message button, no connect")
 		case "skipped_premium":
-			storage.UpdateStatus(db, profileURL, "premium_only")
+			updateStatus(plog, db, profileURL, storage.StatusPremiumOnly, "connect: InMail only")
+		case "skipped_suppressed":
+			updateStatus(plog, db, profileURL, storage.StatusSuppressed, "connect: on suppression list")
+		case "dry_run":
+			dryRunCount++
 		case "failed":
-			log.Printf("❌ Failed: %v", connErr)
+			plog.Warn("❌ Connect failed", logging.Err(connErr))
+			updateStatus(plog, db, profileURL, storage.StatusFailed, fmt.Sprintf("connect: %v", connErr))
+		}
+
+		if tripped {
+			break
 		}
 
 		// Safety Delay
 		if i < len(profiles)-1 {
 			waitTime := 15000 + rand.Intn(15000) // 15-30s delay
-			log.Printf("⏳ Safety delay: %ds...", waitTime/1000)
+			slog.Debug("⏳ Safety delay", "seconds", waitTime/1000)
 			stealth.RandomSleep(waitTime, waitTime+1000)
 		}
 	}
 
-	log.Printf("\n✅ Connect Mode Complete. Sent %d new invites.", successCount)
+	if cfg.DryRun {
+		slog.Info("✅ Connect Mode Complete (dry run, sent nothing)", "walked", dryRunCount)
+		return
+	}
+	slog.Info("✅ Connect Mode Complete", "invites_sent", successCount)
+}
+
+// printDryRunResults lists what this run would have done
+func printDryRunResults(db *sql.DB, runID string) {
+	results, err := storage.GetDryRunResults(db, runID)
+	if err != nil {
+		slog.Warn("⚠️ Failed to read dry-run results", logging.Err(err))
+		return
+	}
+
+	slog.Info("🧪 DRY RUN RESULTS", "count", len(results))
+	for _, r := range results {
+		slog.Info("🧪 Would have done", logging.Profile(r.ProfileURL), "action", r.Action, "outcome", r.Outcome, "text", r.Text)
+	}
 }
 
 // runDemoMode executes search then connect
-func runDemoMode(page *rod.Page, db *sql.DB, cfg *config.Config) {
-	log.Println("🎯 Running Demo Sequence...")
-	runSearchMode(page, db, cfg)
-	
-	log.Println("\n⏳ Waiting 10 seconds before connecting...")
+func runDemoMode(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget) {
+	slog.Info("🎯 Running Demo Sequence")
+	runSearchMode(page, db, cfg, budget)
+
+	slog.Info("⏳ Waiting 10 seconds before connecting")
 	time.Sleep(10 * time.Second)
 
-	runConnectMode(page, db, cfg)
-	log.Println("\n✅ Demo sequence completed!")
+	runConnectMode(page, db, cfg, budget)
+	slog.Info("✅ Demo sequence completed")
 }
 
 // showFinalStatistics displays comprehensive database statistics
-func showFinalStatistics(db *sql.DB, cfg *config.Config) {
-	log.Println("\n==========================================")
-	log.Println("FINAL DATABASE STATISTICS")
-	log.Println("==========================================")
-
+func showFinalStatistics(db *sql.DB, cfg *config.Config, budget *guard.Budget) {
 	stats, _ := storage.GetStats(db)
-	log.Printf("Total Profiles:          %d", stats.Total)
-	log.Printf("├─ Found (ready):        %d", stats.Found)
-	log.Printf("├─ Invited (sent):       %d", stats.Invited)
-	log.Printf("├─ Connected:            %d", stats.Connected)
-	
-	// Daily Stats
-	todaySearch, _ := guard.GetTodayCount(db)
-	todayInvites, _ := guard.GetDailyInviteCount(db)
-	
-	log.Println("\n📅 Today's Performance:")
-	log.Printf("├─ Profiles Collected:   %d / %d", todaySearch, cfg.SearchLimit)
-	log.Printf("└─ Invites Sent:         %d / %d", todayInvites, cfg.InviteLimit)
-	log.Println("==========================================")
+	slog.Info("📊 Final database statistics",
+		"total", stats.Total,
+		"found", stats.ByStatus[storage.StatusFound],
+		"invited", stats.ByStatus[storage.StatusInvited],
+		"pending", stats.ByStatus[storage.StatusPending],
+		"messaged", stats.ByStatus[storage.StatusMessaged],
+		"replied", stats.ByStatus[storage.StatusReplied],
+		"failed", stats.ByStatus[storage.StatusFailed],
+		"suppressed", stats.ByStatus[storage.StatusSuppressed],
+		"opted_out", stats.ByStatus[storage.StatusOptedOut])
+
+	// Everything sent to the site today, from the actions ledger, and what the budget still allows
+	var actions, left []any
+	for _, kind := range storage.AllActionKinds {
+		n, _ := budget.CountToday(kind)
+		actions = append(actions, string(kind), n)
+		if _, ok := budget.Limits(kind); ok {
+			remaining, _ := budget.Remaining(kind)
+			left = append(left, string(kind), remaining)
+		}
+	}
+	slog.Info("🧾 Today's actions", actions...)
+	slog.Info("💰 Budget remaining", left...)
+
+	if trip, err := storage.GetActiveBreakerTrip(db, time.Now()); err == nil && trip != nil {
+		slog.Error("🚨 CIRCUIT BREAKER TRIPPED: no further runs until the cooldown ends or -mode=breaker reset",
+			"signal", trip.Signal,
+			"detail", trip.Detail,
+			"until", trip.Until.Local().Format("2006-01-02 15:04"))
+	}
+
+	if s, err := session.Get(db, cfg.Email); err == nil {
+		if warning := session.ExpiryWarning(s, cfg.SessionWarnWindow, time.Now()); warning != "" {
+			slog.Warn("⚠️ Session: "+warning, "expires_at", s.ExpiresAt.Time)
+		}
+	}
+}
+
+// runMessageMode executes the messaging workflow within the budget
+func runMessageMode(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget) {
+	slog.Info("📨 Starting Message Mode")
+
+	// Follow-up steps come from the campaign's sequence (cfg.Sequence)
+	slog.Info("🪜 Follow-up sequence", "steps", len(cfg.Sequence))
+
+	// The budget sets the batch: checks 'invited' profiles to see if they accepted,
+	// and sends steps that are due until the view or message budget is used up
+	err := linkedin.SendMessages(page, db, cfg, budget)
+	if stopped(err) {
+		return
+	}
+	if err != nil && !overBudget(err) {
+		slog.Error("❌ Message mode error", logging.Err(err))
+	}
+
+	// Read the threads of already messaged profiles so opt-out replies are honoured.
+	// Every thread is read on the profile page, so the views left after the follow-ups set the batch.
+	remaining, err := budget.Remaining(storage.ActionProfileView)
+	if err != nil {
+		slog.Warn("⚠️ Error checking profile view budget", logging.Err(err))
+		return
+	}
+	if remaining <= 0 {
+		slog.Info("🛑 Profile view budget used up. Skipping the reply check")
+	} else if _, err := linkedin.CheckReplies(page, db, cfg, budget, remaining); stopped(err) || overBudget(err) {
+		return
+	} else if err != nil {
+		slog.Error("❌ Reply check error", logging.Err(err))
+	}
+
+	slog.Info("✅ Message Mode Complete")
+}
+
+// runInboxSyncMode stores the inbox threads with known contacts and marks who replied
+func runInboxSyncMode(page *rod.Page, db *sql.DB, cfg *config.Config) {
+	slog.Info("📬 Starting Inbox Sync Mode")
+
+	// Threads are read on the messaging page without opening profiles, so no budget applies;
+	// INBOX_SYNC_LIMIT (default 20, a day or two of replies) sets how many of the newest are read
+	result, err := linkedin.SyncInbox(page, db, cfg, cfg.InboxSyncLimit)
+	if err != nil && !stopped(err) {
+		slog.Error("❌ Inbox sync error", logging.Err(err))
+	}
+	if result != nil {
+		slog.Info("📬 Inbox sync results",
+			"threads", result.Threads,
+			"known_contacts", result.Matched,
+			"new_messages", result.Messages,
+			"replied", result.Replied,
+			"opted_out", result.OptedOut)
+	}
+
+	slog.Info("✅ Inbox Sync Complete")
+}
+
+// runDaemonMode stays resident: it sleeps until working hours, runs cfg.DaemonSteps every
+// cfg.DaemonWait within the budget, and exits cleanly on SIGINT/SIGTERM without keyboard input.
+// Working hours and shutdown are re-checked before every action.
+func runDaemonMode(cfg *config.Config, runID string) {
+	slog.Info("👻 Starting Daemon Mode",
+		"steps", cfg.DaemonSteps,
+		"interval", cfg.DaemonInterval,
+		"schedule", cfg.Schedule.String())
+
+	// The first signal stops after the current action; once it is handled a second one kills the process
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		signal.Stop(sigs)
+		slog.Info("🛑 Shutdown requested. Stopping after the current action (signal again to quit now)", "signal", sig.String())
+		cancel()
+	}()
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	if _, err := storage.UseCampaign(db, cfg.Campaign); err != nil {
+		fatal("❌ Failed to select campaign", logging.Err(err))
+	}
+	budget := guard.NewBudget(db, cfg.Budgets(), cfg.Schedule.Location())
+	breaker := guard.NewBreaker(db, cfg.BreakerWait, cfg.BreakerMaxFailures)
+	guard.SetBreaker(breaker)
+	defer guard.SetBreaker(nil)
+
+	guard.SetCheckpoint(func() error {
+		if ctx.Err() != nil {
+			return errors.New("shutdown requested")
+		}
+		return cfg.Schedule.Check(time.Now())
+	})
+	defer guard.SetCheckpoint(nil)
+
+	for cycle := 1; ; cycle++ {
+		if err := guard.WaitForWorkingHours(ctx, cfg.Schedule); err != nil {
+			if ctx.Err() == nil {
+				fatal("❌ Cannot schedule the next cycle", logging.Err(err))
+			}
+			break
+		}
+		// A tripped breaker pauses the daemon until its cooldown ends (or it is reset)
+		if trip, err := breaker.Active(); err != nil {
+			slog.Warn("⚠️ Failed to check circuit breaker", logging.Err(err))
+		} else if trip != nil {
+			slog.Error("🚨 CIRCUIT BREAKER TRIPPED: pausing until the cooldown ends",
+				"signal", trip.Signal,
+				"detail", trip.Detail,
+				"until", trip.Until.Local().Format("2006-01-02 15:04"))
+			if err := guard.Wait(ctx, time.Until(trip.Until)); err != nil {
+				break
+			}
+			continue
+		}
+		runDaemonCycle(db, cfg, budget, cycle)
+		showFinalStatistics(db, cfg, budget)
+
+		if ctx.Err() != nil {
+			break
+		}
+		slog.Info("⏳ Cycle complete. Waiting for the next one", "cycle", cycle, "interval", cfg.DaemonInterval)
+		if err := guard.Wait(ctx, cfg.DaemonWait); err != nil {
+			break
+		}
+	}
+
+	if cfg.DryRun {
+		printDryRunResults(db, runID)
+	}
+	slog.Info("✅ Daemon stopped")
+}
+
+// runDaemonCycle opens a browser, logs in and runs each of cfg.DaemonSteps, stopping early once
+// guard.Proceed says so. A failure ends the cycle, not the daemon.
+func runDaemonCycle(db *sql.DB, cfg *config.Config, budget *guard.Budget, cycle int) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("❌ Cycle aborted", "cycle", cycle, "panic", fmt.Sprint(r))
+		}
+	}()
+	slog.Info("🔁 Starting cycle", "cycle", cycle)
+
+	b, err := browser.NewBrowser(cfg.Headless)
+	if err != nil {
+		slog.Error("❌ Failed to initialize browser", logging.Err(err))
+		return
+	}
+	defer b.MustClose()
+
+	page, err := browser.NewStealthPage(b)
+	if err != nil {
+		slog.Error("❌ Failed to create stealth page", logging.Err(err))
+		return
+	}
+	if err := linkedin.Login(b, page, db, cfg); err != nil {
+		slog.Error("❌ LinkedIn login failed", logging.Err(err))
+		return
+	}
+
+	for _, step := range cfg.DaemonSteps {
+		if err := guard.Proceed(); stopped(err) {
+			return
+		}
+		switch step {
+		case "search":
+			runSearchMode(page, db, cfg, budget)
+		case "connect":
+			runConnectMode(page, db, cfg, budget)
+		case "message":
+			runMessageMode(page, db, cfg, budget)
+		case "inbox-sync":
+			runInboxSyncMode(page, db, cfg)
+		}
+	}
 }
 
+// runDoctorSnapshots checks every selector against saved HTML pages
+func runDoctorSnapshots(cfg *config.Config, dir, reportFile string) {
+	slog.Info("🩺 Starting Selector Doctor (snapshots)")
 
-// runMessageMode executes the messaging workflow
-func runMessageMode(page *rod.Page, db *sql.DB, cfg *config.Config) {
-	log.Println("📨 Starting Message Mode...")
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil || len(files) == 0 {
+		fatal("❌ No .html snapshots found", "dir", dir)
+	}
+	sort.Strings(files)
+
+	b, err := browser.NewBrowser(cfg.Headless)
+	if err != nil {
+		fatal("❌ Failed to initialize browser", logging.Err(err))
+	}
+	defer b.MustClose()
+	page := b.MustPage()
+
+	report := linkedin.NewDoctorReport(cfg.Selectors)
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			slog.Warn("⚠️ Skipping snapshot", "file", file, logging.Err(err))
+			continue
+		}
+		page.MustNavigate("file://" + filepath.ToSlash(abs))
+		page.MustWaitLoad()
+		linkedin.CheckSelectors(page, cfg.Selectors, filepath.Base(file), report)
+	}
+
+	writeDoctorReport(report, reportFile)
+}
+
+// runDoctorLive checks every selector against the logged-in page and any extra URLs
+func runDoctorLive(page *rod.Page, cfg *config.Config, urls, reportFile string) {
+	slog.Info("🩺 Starting Selector Doctor (live)")
+
+	report := linkedin.NewDoctorReport(cfg.Selectors)
+	linkedin.CheckSelectors(page, cfg.Selectors, page.MustInfo().URL, report)
+
+	for _, u := range strings.Split(urls, ",") {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		page.MustNavigate(u)
+		page.MustWaitLoad()
+		stealth.RandomSleep(3000, 5000)
+		linkedin.CheckSelectors(page, cfg.Selectors, u, report)
+	}
+
+	writeDoctorReport(report, reportFile)
+}
+
+// writeDoctorReport prints the JSON report (or saves it) and logs keys that matched nowhere
+func writeDoctorReport(report *linkedin.DoctorReport, reportFile string) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		slog.Error("❌ Failed to encode doctor report", logging.Err(err))
+		return
+	}
+
+	if reportFile == "" {
+		fmt.Println(string(data))
+	} else if err := os.WriteFile(reportFile, data, 0644); err != nil {
+		slog.Error("❌ Failed to write doctor report", logging.Err(err))
+		return
+	} else {
+		slog.Info("💾 Doctor report written", "file", reportFile)
+	}
+
+	missing := 0
+	for _, key := range selectors.Keys() {
+		// Account warnings only show when something is wrong
+		if strings.HasPrefix(string(key), "warning.") {
+			continue
+		}
+		if report.Summary[string(key)].Status == linkedin.MatchNone {
+			slog.Warn("❌ Selector key matched nothing on any page", "key", key)
+			missing++
+		}
+	}
+	slog.Info("✅ Doctor complete", "matched", len(selectors.Keys())-missing, "keys", len(selectors.Keys()))
+}
+
+// runMigrateMode applies pending schema migrations (unless statusOnly) and prints the schema state
+func runMigrateMode(statusOnly bool) {
+	slog.Info("🗄️ Starting Migrate Mode")
+
+	db, err := storage.OpenDB()
+	if err != nil {
+		fatal("❌ Failed to open database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	if !statusOnly {
+		applied, err := storage.Migrate(db)
+		if err != nil {
+			fatal("❌ Migration failed", logging.Err(err))
+		}
+		slog.Info("✅ Applied migrations", "count", len(applied))
+	}
+
+	states, err := storage.GetMigrationStatus(db)
+	if err != nil {
+		fatal("❌ Failed to read migration status", logging.Err(err))
+	}
+
+	pending := 0
+	fmt.Println("\nVERSION  STATUS   APPLIED AT           NAME")
+	for _, st := range states {
+		status, appliedAt := "pending", "-"
+		if st.Applied {
+			status, appliedAt = "applied", st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		} else {
+			pending++
+		}
+		fmt.Printf("%-8d %-8s %-20s %s\n", st.Version, status, appliedAt, st.Name)
+	}
+	fmt.Printf("\n%d migration(s), %d pending\n", len(states), pending)
+}
+
+// runDedupeMode merges profile rows whose URLs share a canonical form
+func runDedupeMode() {
+	slog.Info("🔗 Starting Dedupe Mode")
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	result, err := storage.DedupeProfiles(db)
+	if err != nil {
+		fatal("❌ Dedupe failed (no changes were saved)", logging.Err(err))
+	}
+
+	slog.Info("✅ Dedupe complete", "groups", result.Groups, "removed", result.Removed, "rewritten", result.Rewritten)
+	storage.PrintStats(db)
+}
+
+// runSuppressMode imports a CSV of people never to contact and marks matching profiles 'suppressed'.
+// With an empty csvPath it prints the current list instead.
+func runSuppressMode(csvPath string) {
+	slog.Info("🚫 Starting Suppress Mode")
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	if csvPath == "" {
+		list, err := storage.GetSuppressions(db)
+		if err != nil {
+			fatal("❌ Failed to read suppression list", logging.Err(err))
+		}
+		fmt.Printf("%-8s  %-50s  %s\n", "KIND", "VALUE", "REASON")
+		for _, s := range list {
+			fmt.Printf("%-8s  %-50s  %s\n", s.Kind, s.Value, s.Reason)
+		}
+		slog.Info("📋 Suppression entries. Import more with --mode=suppress <file.csv>", "count", len(list))
+		return
+	}
+
+	added, total, err := storage.ImportSuppressionCSV(db, csvPath)
+	if err != nil {
+		fatal("❌ Import failed", "added", added, logging.Err(err))
+	}
+	slog.Info("✅ Imported suppression list", "file", csvPath, "added", added, "already_listed", total-added)
+
+	suppressed, err := storage.ApplySuppressions(db)
+	if err != nil {
+		fatal("❌ Failed to apply suppression list", logging.Err(err))
+	}
+	slog.Info("✅ Existing profiles newly suppressed", "count", suppressed)
+}
+
+// runCampaignsMode lists the configured campaigns with their settings and stored profile counts
+func runCampaignsMode(cfg *config.Config) {
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	stored, counts, err := storage.GetCampaigns(db)
+	if err != nil {
+		fatal("❌ Failed to read campaigns", logging.Err(err))
+	}
+	profiles := make(map[string]int)
+	for _, c := range stored {
+		profiles[c.Name] = counts[c.ID]
+	}
+
+	fmt.Printf("%-16s  %-8s  %-8s  %-8s  %-40s  %s\n", "CAMPAIGN", "INVITES", "PROFILES", "STORED", "QUERIES", "SCHEDULE")
+	for _, name := range cfg.CampaignNames() {
+		c := *cfg
+		if err := c.UseCampaign(name); err != nil {
+			fatal("❌ Failed to select campaign", logging.Err(err))
+		}
+		fmt.Printf("%-16s  %-8s  %-8s  %-8d  %-40s  %s\n",
+			name, formatCap(c.CampaignInviteLimit), formatCap(c.CampaignSearchLimit), profiles[name], fmt.Sprintf("%q", c.SearchQueries), c.Schedule)
+	}
+}
+
+// formatCap prints a campaign's daily cap, "-" if it has none
+func formatCap(limit *int) string {
+	if limit == nil {
+		return "-"
+	}
+	return strconv.Itoa(*limit)
+}
+
+// runHistoryMode prints everything sent to one profile, with its status history
+func runHistoryMode(profileURL string) {
+	if profileURL == "" {
+		fatal("❌ History mode needs -profile=<profile URL>")
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	profile, err := storage.GetProfile(db, profileURL)
+	if err == sql.ErrNoRows {
+		fatal("❌ Profile is not in the database", logging.Profile(profileURL))
+	}
+	if err != nil {
+		fatal("❌ Failed to read profile", logging.Err(err))
+	}
+	sent, err := storage.GetOutboundMessages(db, profile.URL)
+	if err != nil {
+		fatal("❌ Failed to read sent messages", logging.Err(err))
+	}
+	events, err := storage.GetProfileEvents(db, profile.URL)
+	if err != nil {
+		fatal("❌ Failed to read status history", logging.Err(err))
+	}
+
+	fmt.Printf("\n%s (%s)\n", profile.FullName, profile.URL)
+	fmt.Printf("Status: %s\n", profile.Status)
+
+	fmt.Printf("\n📤 Sent (%d):\n", len(sent))
+	for _, m := range sent {
+		text := m.Text
+		if text == "" {
+			text = "(invite without a note)"
+		}
+		fmt.Printf("  %s  %-8s  %s  [run %s]\n", m.CreatedAt.Format("2006-01-02 15:04"), m.Kind, m.Template, m.RunID)
+		fmt.Printf("      %s\n", strings.ReplaceAll(text, "\n", "\n      "))
+	}
+
+	fmt.Printf("\n🔁 Status history (%d):\n", len(events))
+	for _, e := range events {
+		from := string(e.FromStatus)
+		if from == "" {
+			from = "-"
+		}
+		fmt.Printf("  %s  %s -> %s  %s\n", e.CreatedAt.Format("2006-01-02 15:04"), from, e.ToStatus, e.Reason)
+	}
+}
+
+// runConfigCheckMode prints every setting with its resolved value and source, secrets masked
+func runConfigCheckMode(cfg *config.Config) {
+	file := cfg.ConfigFile
+	if file == "" {
+		file = "(none, CONFIG_FILE is not set)"
+	}
+	fmt.Printf("\nConfig file: %s\n", file)
+	fmt.Printf("Campaign:    %s\n\n", cfg.Campaign)
+
+	fmt.Printf("%-20s  %-26s  %-48s  %s\n", "SETTING", "ENV", "VALUE", "SOURCE")
+	for _, r := range cfg.Resolved() {
+		value := r.Value
+		if runes := []rune(value); len(runes) > 48 {
+			value = string(runes[:45]) + "..."
+		}
+		env := r.Env
+		if env == "" {
+			env = "-"
+		}
+		fmt.Printf("%-20s  %-26s  %-48s  %s\n", r.Key, env, value, r.Source)
+	}
+
+	fmt.Printf("\nSelectors:          %s v%d\n", cfg.Selectors.Name(), cfg.Selectors.Version())
+	fmt.Printf("Campaigns:          %s\n", strings.Join(cfg.CampaignNames(), ", "))
+	fmt.Printf("Follow-up sequence: %d step(s)\n", len(cfg.Sequence))
+	fmt.Printf("Schedule:           %s, %d holiday(s)\n", cfg.Schedule, cfg.Schedule.Holidays())
+	slog.Info("✅ Configuration is valid")
+}
+
+// runSecretsMode reports what the encrypted secrets file holds. With doImport it first stores the
+// configured password and moves the saved session cookies into it.
+func runSecretsMode(cfg *config.Config, doImport bool) {
+	store := cfg.Secrets
+	if store == nil {
+		fatal("❌ SECRETS_FILE is not set. Set it and SECRETS_PASSPHRASE (or SECRETS_KEY_FILE) first.")
+	}
+
+	if doImport {
+		if store.Password() != cfg.Password {
+			if err := store.SetPassword(cfg.Password); err != nil {
+				fatal("❌ Failed to store password", logging.Err(err))
+			}
+			slog.Info("🔐 Password stored in the secrets file", "from", cfg.Sources["password"], "file", store.Path())
+		}
+		db, err := storage.InitDB()
+		if err != nil {
+			fatal("❌ Failed to initialize database", logging.Err(err))
+		}
+		defer storage.CloseDB(db)
+		if err := session.Migrate(db, store, cfg.Email); err != nil {
+			fatal("❌ Failed to import cookies", logging.Err(err))
+		}
+		if cfg.Sources["password"] != "secrets file "+cfg.SecretsFile {
+			slog.Info("👉 Now remove the password from its plain-text source", "source", cfg.Sources["password"])
+		}
+	}
+
+	stored := func(ok bool) string {
+		if ok {
+			return "stored"
+		}
+		return "not stored"
+	}
+	fmt.Printf("\nSecrets file: %s\n", store.Path())
+	fmt.Printf("Password:     %s\n", stored(store.Password() != ""))
+	fmt.Printf("Cookies:      %s\n", stored(store.Cookies() != nil))
+}
+
+// runSessionStatusMode reports how long the saved login session stays valid, without a browser
+func runSessionStatusMode(cfg *config.Config) {
+	db, err := storage.InitDB()
+	if err != nil {
+		fatal("❌ Failed to initialize database", logging.Err(err))
+	}
+	defer storage.CloseDB(db)
+
+	if err := session.Migrate(db, cfg.Secrets, cfg.Email); err != nil {
+		slog.Warn("⚠️ Failed to migrate saved cookies", logging.Err(err))
+	}
+	s, err := session.Get(db, cfg.Email)
+	if err == session.ErrNoSession {
+		slog.Info("🍪 No saved session. The next run logs in with the password", "account", cfg.Email)
+		return
+	}
+	if err != nil {
+		fatal("❌ Failed to read session", logging.Err(err))
+	}
 
-	//  DYNAMIC TEMPLATE: Load from Config
-	template := cfg.FollowupMessageTemplate
+	where := "database"
+	if s.Cookies == nil {
+		where = "secrets file " + cfg.SecretsFile
+	}
+	now := time.Now()
+	fmt.Printf("\nAccount:   %s\n", s.Account)
+	fmt.Printf("Stored in: %s (%d cookies)\n", where, s.CookieCount)
+	fmt.Printf("Captured:  %s (%s ago)\n", s.CapturedAt.Local().Format("2006-01-02 15:04"), session.FormatDuration(now.Sub(s.CapturedAt)))
+
+	remaining, ok := session.Remaining(s, now)
+	switch {
+	case !ok:
+		fmt.Println("Expires:   unknown (no auth cookie has an expiry date)")
+	case remaining <= 0:
+		fmt.Printf("Expires:   %s (cookie %s, expired %s ago)\n", s.ExpiresAt.Time.Local().Format("2006-01-02 15:04"), s.AuthCookie, session.FormatDuration(remaining))
+	default:
+		fmt.Printf("Expires:   %s (cookie %s, %s left)\n", s.ExpiresAt.Time.Local().Format("2006-01-02 15:04"), s.AuthCookie, session.FormatDuration(remaining))
+	}
+	if warning := session.ExpiryWarning(s, cfg.SessionWarnWindow, now); warning != "" {
+		slog.Warn("⚠️ Session: "+warning, "expires_at", s.ExpiresAt.Time)
+	}
+}
 
-	// Set a safe batch limit (e.g., 10 messages per run)
-	// checks 'invited' profiles to see if they accepted
-	err := linkedin.SendMessages(page, db, template, 10)
+// runBreakerMode reports whether the circuit breaker is tripped and lists recent trips.
+// With reset it clears the active trip first, so the next run may start.
+func runBreakerMode(reset bool) {
+	slog.Info("🚨 Starting Breaker Mode")
+
+	db, err := storage.InitDB()
 	if err != nil {
-		log.Printf("❌ Message mode error: %v", err)
+		fatal("❌ Failed to initialize database", logging.Err(err))
 	}
+	defer storage.CloseDB(db)
 
-	log.Println("✅ Message Mode Complete.")
-}
\ No newline at end of file
+	now := time.Now()
+	if reset {
+		cleared, err := storage.ClearBreakerTrips(db, now)
+		if err != nil {
+			fatal("❌ Failed to reset circuit breaker", logging.Err(err))
+		}
+		slog.Info("✅ Circuit breaker reset", "cleared", cleared)
+	}
+
+	trip, err := storage.GetActiveBreakerTrip(db, now)
+	if err != nil {
+		fatal("❌ Failed to read circuit breaker", logging.Err(err))
+	}
+	if trip == nil {
+		fmt.Println("\nCircuit breaker: closed (runs may start)")
+	} else {
+		fmt.Printf("\nCircuit breaker: TRIPPED by %s until %s (%s left)\n", trip.Signal, trip.Until.Local().Format("2006-01-02 15:04"), session.FormatDuration(trip.Until.Sub(now)))
+		fmt.Printf("Detail:          %s\n", trip.Detail)
+	}
+
+	trips, err := storage.GetBreakerTrips(db, 10)
+	if err != nil {
+		fatal("❌ Failed to read circuit breaker trips", logging.Err(err))
+	}
+	fmt.Printf("\nRecent trips (%d):\n", len(trips))
+	for _, t := range trips {
+		state := "expired"
+		switch {
+		case t.ClearedAt.Valid:
+			state = "reset " + t.ClearedAt.Time.Local().Format("2006-01-02 15:04")
+		case t.Until.After(now):
+			state = "active"
+		}
+		fmt.Printf("  %s  %-13s %-28s %s\n", t.TrippedAt.Local().Format("2006-01-02 15:04"), t.Signal, state, t.Detail)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/SNKT2024/linkedin-automation/internal/fakesite"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// fakesite serves a local imitation of LinkedIn so the bot can be exercised end-to-end.
+// Point the bot at it with LINKEDIN_BASE_URL=http://<addr> (and HEADLESS=true for CI).
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8090", "Address to listen on")
+	email := flag.String("email", "test@example.com", "Email accepted by the login form")
+	password := flag.String("password", "password", "Password accepted by the login form")
+	flag.Parse()
+
+	site := fakesite.New(*email, *password)
+
+	slog.Info("🧪 Fake LinkedIn listening", "url", "http://"+*addr, "email", *email, "password", *password)
+	if err := http.ListenAndServe(*addr, site.Handler()); err != nil {
+		slog.Error("❌ Fake site stopped", logging.Err(err))
+		os.Exit(1)
+	}
+}
@@ -1,7 +1,6 @@
 package browser
 
 import (
-	"log"
 	"math/rand"
 
 	"github.com/go-rod/rod"
@@ -39,24 +38,25 @@ var viewports = []Viewport{
 	{Width: 1280, Height: 720},  // HD
 }
 
-// NewBrowser initializes and returns a Rod browser instance in headful mode with random fingerprinting.
-func NewBrowser() (*rod.Browser, error) {
-	log.Println("Initializing browser with random fingerprinting...")
+// NewBrowser initializes and returns a Rod browser instance with random fingerprinting.
+// Headful by default; headless is meant for CI runs against a local fake site.
+func NewBrowser(headless bool) (*rod.Browser, error) {
+	logger.Info("Initializing browser with random fingerprinting", "headless", headless)
 
 	// Select random User Agent
 	randomUA := userAgents[rand.Intn(len(userAgents))]
-	log.Printf("Selected User Agent: %s", randomUA)
+	logger.Debug("Selected User Agent", "user_agent", randomUA)
 
 	// Configure launcher with random User Agent and fixed window size
 	url := launcher.New().
-		Headless(false).
+		Headless(headless).
 		Leakless(false).
 		Set("user-agent", randomUA).
 		Set("window-size", "1920,1080"). // Force large physical window to prevent geometry detection
 		MustLaunch()
 
 	browser := rod.New().ControlURL(url).MustConnect()
-	log.Println("Browser initialized successfully.")
+	logger.Info("Browser initialized")
 	return browser, nil
 }
 
@@ -67,10 +67,10 @@ func NewStealthPage(browser *rod.Browser) (*rod.Page, error) {
 
 	// Select random viewport
 	randomViewport := viewports[rand.Intn(len(viewports))]
-	log.Printf("Selected Viewport: %dx%d", randomViewport.Width, randomViewport.Height)
+	logger.Debug("Selected Viewport", "width", randomViewport.Width, "height", randomViewport.Height)
 
 	// Set the viewport
 	page.MustSetViewport(randomViewport.Width, randomViewport.Height, 1.0, false)
 
 	return page, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCampaign is the campaign made of the plain settings (config file and environment).
+// The campaigns section may define it too, to override some of them.
+const DefaultCampaign = "default"
+
+// Campaign is one named outreach effort from the config file's campaigns section.
+// Empty fields keep the resolved setting. The daily caps are the campaign's own, on top of the
+// account-wide limits; nil means none.
+type Campaign struct {
+	Name             string       `yaml:"name"`
+	Queries          []string     `yaml:"queries"`   // Search keywords, run in order by search mode
+	MaxPages         int          `yaml:"max_pages"` // Result pages per query
+	ConnectTemplate  string       `yaml:"connect_template"`
+	FollowupTemplate string       `yaml:"followup_template"`
+	FollowupSteps    []StepConfig `yaml:"followup_steps"` // Multi-step sequence; replaces followup_template
+	InviteLimit      *int         `yaml:"daily_invite_limit"`
+	SearchLimit      *int         `yaml:"daily_search_limit"`
+	WorkStart        string       `yaml:"working_hours_start"` // HH:MM
+	WorkEnd          string       `yaml:"working_hours_end"`   // HH:MM
+	Schedule         []string     `yaml:"schedule"`            // Weekly windows; replace the working hours
+}
+
+// campaignKeys are the keys a campaign may set in the config file
+var campaignKeys = []string{
+	"name", "queries", "max_pages", "connect_template", "followup_template", "followup_steps",
+	"daily_invite_limit", "daily_search_limit", "working_hours_start", "working_hours_end", "schedule",
+}
+
+// UnmarshalYAML decodes a campaign from the config file, rejecting keys it does not know so a
+// misspelled limit is not silently ignored.
+func (c *Campaign) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; !slices.Contains(campaignKeys, key.Value) {
+				return fmt.Errorf("line %d: unknown campaign field %q", key.Line, key.Value)
+			}
+		}
+	}
+	type plain Campaign // Without this method, so Decode does not recurse
+	return node.Decode((*plain)(c))
+}
+
+var campaignName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateCampaigns checks every campaign, returning one problem per invalid field.
+func validateCampaigns(campaigns []Campaign) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for i, c := range campaigns {
+		where := fmt.Sprintf("campaigns[%d]", i)
+		if c.Name != "" {
+			where = fmt.Sprintf("campaign %q", c.Name)
+		}
+		if !campaignName.MatchString(c.Name) {
+			problems = append(problems, where+": name must be lowercase letters, digits, '-' or '_'")
+		}
+		if seen[c.Name] {
+			problems = append(problems, where+": defined twice")
+		}
+		seen[c.Name] = true
+
+		for j, q := range c.Queries {
+			if strings.TrimSpace(q) == "" {
+				problems = append(problems, fmt.Sprintf("%s: queries[%d] is empty", where, j))
+			}
+		}
+		if c.MaxPages < 0 {
+			problems = append(problems, where+": max_pages must not be negative")
+		}
+		if c.InviteLimit != nil && *c.InviteLimit < 0 {
+			problems = append(problems, where+": daily_invite_limit must not be negative")
+		}
+		if c.SearchLimit != nil && *c.SearchLimit < 0 {
+			problems = append(problems, where+": daily_search_limit must not be negative")
+		}
+		for _, hm := range []string{c.WorkStart, c.WorkEnd} {
+			if hm != "" && !isValidTimeFormat(hm) {
+				problems = append(problems, fmt.Sprintf("%s: working hours %q must be in HH:MM format", where, hm))
+			}
+		}
+		for _, entry := range c.Schedule {
+			if err := guard.NewSchedule(time.Local).AddWindow(entry); err != nil {
+				problems = append(problems, where+": schedule: "+err.Error())
+			}
+		}
+		if c.ConnectTemplate != "" {
+			if t, err := template.Parse(where+" connect_template", c.ConnectTemplate); err != nil {
+				problems = append(problems, err.Error())
+			} else if err := t.CheckLength(template.ConnectNoteLimit); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+		if c.FollowupTemplate != "" && len(c.FollowupSteps) > 0 {
+			problems = append(problems, where+": set either followup_template or followup_steps, not both")
+		}
+		_, stepProblems := parseSteps(where, c.FollowupSteps)
+		problems = append(problems, stepProblems...)
+		if c.FollowupTemplate != "" {
+			if _, err := template.Parse(where+" followup_template", c.FollowupTemplate); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	return problems
+}
+
+// CampaignNames returns the names of every campaign that can be selected.
+func (c *Config) CampaignNames() []string {
+	names := []string{DefaultCampaign}
+	for _, camp := range c.Campaigns {
+		if camp.Name != DefaultCampaign {
+			names = append(names, camp.Name)
+		}
+	}
+	return names
+}
+
+// UseCampaign applies the named campaign's settings over the resolved ones, records the campaign
+// as their source, and records it in c.Campaign. An empty name selects the default campaign.
+func (c *Config) UseCampaign(name string) error {
+	if name == "" {
+		name = DefaultCampaign
+	}
+
+	var camp *Campaign
+	for i := range c.Campaigns {
+		if c.Campaigns[i].Name == name {
+			camp = &c.Campaigns[i]
+		}
+	}
+	if camp == nil {
+		if name != DefaultCampaign {
+			return fmt.Errorf("unknown campaign %q (available: %s)", name, strings.Join(c.CampaignNames(), ", "))
+		}
+		c.Campaign = name
+		return nil
+	}
+
+	// Keep the caller's source map intact when c is a copy
+	c.Sources = maps.Clone(c.Sources)
+	src := fmt.Sprintf("campaign %q (%s)", name, c.ConfigFile)
+
+	if len(camp.Queries) > 0 {
+		c.SearchKeyword = camp.Queries[0]
+		c.SearchQueries = camp.Queries
+		c.setSource("queries", src)
+	}
+	if camp.MaxPages > 0 {
+		c.MaxPages = camp.MaxPages
+		c.setSource("max_pages", src)
+	}
+	// The campaign's caps count only its own activity, within the account-wide daily limits
+	c.CampaignInviteLimit = camp.InviteLimit
+	c.CampaignSearchLimit = camp.SearchLimit
+	if camp.WorkStart != "" {
+		c.WorkStart = camp.WorkStart
+		c.setSource("working_hours_start", src)
+	}
+	if camp.WorkEnd != "" {
+		c.WorkEnd = camp.WorkEnd
+		c.setSource("working_hours_end", src)
+	}
+	if len(camp.Schedule) > 0 {
+		c.ScheduleDays = camp.Schedule
+		c.setSource("schedule", src)
+	} else if (camp.WorkStart != "" || camp.WorkEnd != "") && len(c.ScheduleDays) > 0 {
+		// The campaign's working hours apply every day, in place of the weekly schedule
+		c.ScheduleDays = nil
+		c.setSource("schedule", "overridden by "+src)
+	}
+	if camp.ConnectTemplate != "" {
+		c.ConnectMessageTemplate = camp.ConnectTemplate
+		c.setSource("connect_template", src)
+	}
+	if camp.FollowupTemplate != "" {
+		c.FollowupMessageTemplate = camp.FollowupTemplate
+		c.FollowupSteps = nil
+		c.setSource("followup_template", src)
+	}
+	if len(camp.FollowupSteps) > 0 {
+		c.FollowupSteps = camp.FollowupSteps
+		c.setSource("followup_steps", fmt.Sprintf("campaign %q", name))
+	}
+	if err := loadTemplates(c); err != nil {
+		return fmt.Errorf("campaign %s: %w", name, err)
+	}
+	if problems := c.loadSchedule(); len(problems) > 0 {
+		return fmt.Errorf("campaign %s: %s", name, strings.Join(problems, "; "))
+	}
+
+	c.Campaign = name
+	return nil
+}
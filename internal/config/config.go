@@ -2,148 +2,515 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/optout"
+	"github.com/SNKT2024/linkedin-automation/internal/secrets"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/SNKT2024/linkedin-automation/internal/template"
 	"github.com/joho/godotenv"
 )
 
-// Config holds all configuration values loaded from environment variables
+// Config holds all configuration values, resolved from defaults, the config file and environment variables
 type Config struct {
-    // LinkedIn Credentials
-    Email    string
-    Password string
-
-    // Search Settings
-    SearchKeyword  string
-    SearchLocation string
-    MaxPages       int
-
-    // Stealth & Human Behavior
-    DelayFactor float64
-    ScrollMin   int
-    ScrollMax   int
-
-    // Safety Limits (Daily)
-    InviteLimit int
-    SearchLimit int
-
-    // Working Hours (24h format)
-    WorkStart string
-    WorkEnd   string
-
-    // Message Templates
-    ConnectMessageTemplate  string
+	// Config file (CONFIG_FILE) and where each setting came from, by config file key
+	ConfigFile string
+	Sources    map[string]string
+
+	// LinkedIn Credentials
+	Email    string
+	Password string
+
+	// Encrypted password and session cookie store (SECRETS_FILE), unlocked by SECRETS_KEY_FILE
+	// or SECRETS_PASSPHRASE. Nil when no secrets file is configured.
+	SecretsFile    string
+	SecretsKeyFile string
+	Secrets        *secrets.Store
+
+	// Warn in the run summary when the saved login session expires within this window (e.g. "3d")
+	SessionExpiryWarning string
+	SessionWarnWindow    time.Duration
+
+	// Site Settings
+	Endpoints Endpoints
+	Headless  bool
+
+	// UI Selectors (SELECTORS_FILE, or the embedded default when unset)
+	SelectorsFile string
+	Selectors     *selectors.Registry
+
+	// Opt-out detection rules for replies (OPT_OUT_FILE, or the embedded default when unset)
+	OptOutFile string
+	OptOut     *optout.Classifier
+
+	// Search Settings
+	SearchKeyword  string
+	SearchQueries  []string // Every query search mode runs, in order; SearchKeyword is the first
+	SearchLocation string
+	MaxPages       int
+
+	// Stealth & Human Behavior
+	DelayFactor float64
+	ScrollMin   int
+	ScrollMax   int
+
+	// Safety Limits (Daily, since midnight, for the whole account)
+	InviteLimit  int
+	SearchLimit  int // Profiles collected
+	MessageLimit int
+	ViewLimit    int // Profile pages opened
+
+	// The selected campaign's own daily caps, counting only its activity; nil when it sets none
+	CampaignInviteLimit *int
+	CampaignSearchLimit *int
+
+	// Budget ceilings over the last hour and the last 7 days, on top of the daily limits (see Budgets)
+	HourlyInviteLimit  int
+	WeeklyInviteLimit  int
+	HourlySearchLimit  int
+	WeeklySearchLimit  int
+	HourlyMessageLimit int
+	WeeklyMessageLimit int
+	HourlyViewLimit    int
+	WeeklyViewLimit    int
+
+	// Working Hours (24h format), every day unless ScheduleDays is set
+	WorkStart string
+	WorkEnd   string
+
+	// Schedule: IANA timezone ("" = the host's), weekly windows ("mon-fri 09:00-17:00") that replace
+	// the working hours above, and days off (dates, plus events from a .ics file).
+	// Built at load into Schedule, which every mode checks.
+	Timezone     string
+	ScheduleDays []string
+	Holidays     []string
+	HolidaysFile string
+	Schedule     *guard.Schedule
+
+	// Message Templates (raw text, and parsed/validated at load)
+	ConnectMessageTemplate  string
 	FollowupMessageTemplate string
+	ConnectTemplate         *template.Template
+	FollowupTemplate        *template.Template
+	FollowupSteps           []StepConfig   // Multi-step sequence from the config file (or campaign); replaces FollowupTemplate
+	Sequence                []FollowupStep // Follow-up steps sent by message mode; FollowupTemplate alone by default
+
+	// Inbox sync: how many of the newest conversations a run reads
+	InboxSyncLimit int
+
+	// Logging: level (debug, info, warn, error) and format (text or json)
+	LogLevel  string
+	LogFormat string
+
+	// Execution Defaults
+	DefaultMode string
+	DryRun      bool // Walk every flow but never click Connect/Send or change profile status
 
-    // Execution Defaults
-    DefaultMode string
+	// Daemon mode: the modes each cycle runs, in order, and how long to wait between cycles
+	DaemonSteps    []string
+	DaemonInterval string
+	DaemonWait     time.Duration
+
+	// Circuit breaker: how long account warnings stop the bot, and how many unexplained
+	// failures in a row count as one (0 = never)
+	BreakerCooldown    string
+	BreakerWait        time.Duration
+	BreakerMaxFailures int
+
+	// Campaigns (the config file's campaigns section). UseCampaign applies one of them over the settings above.
+	Campaigns []Campaign
+	Campaign  string // Name of the campaign in use
 }
 
-// Load reads configuration from environment variables and returns a Config struct.
-// Automatically loads .env file and returns an error if required credentials are missing.
+// DaemonModes are the modes daemon_steps may list.
+var DaemonModes = []string{"search", "connect", "message", "inbox-sync"}
+
+// Load builds the configuration from, in increasing precedence: built-in defaults, the YAML
+// config file named by CONFIG_FILE, and environment variables (a .env file is loaded first).
+// Every invalid or missing value is reported in one error, each with the source it came from.
 func Load() (*Config, error) {
-    // Load .env file (ignore error if file doesn't exist - allow system env vars)
-    _ = godotenv.Load()
+	// Load .env file (ignore error if file doesn't exist - allow system env vars)
+	_ = godotenv.Load()
 
-    // Required credentials
-    email := os.Getenv("LINKEDIN_EMAIL")
-    password := os.Getenv("LINKEDIN_PASSWORD")
+	cfg := defaultConfig()
+	cfg.ConfigFile = os.Getenv("CONFIG_FILE")
+	table := settings(cfg)
+	cfg.Sources = make(map[string]string, len(table))
+	for _, s := range table {
+		cfg.Sources[s.key] = "default"
+	}
 
-    if email == "" || password == "" {
-        return nil, errors.New("LINKEDIN_EMAIL and LINKEDIN_PASSWORD must be set in .env file")
-    }
+	var problems []string
+	if cfg.ConfigFile != "" {
+		problems = append(problems, applyFile(cfg, table, cfg.ConfigFile)...)
+	}
+	problems = append(problems, applyEnv(cfg, table)...)
+	cfg.useKeyword()
+	problems = append(problems, cfg.openSecrets()...)
+	problems = append(problems, cfg.validate()...)
+	problems = append(problems, cfg.loadFiles()...)
 
-    cfg := &Config{
-        Email:    email,
-        Password: password,
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration (%d problem(s)):\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+	}
+	return cfg, nil
+}
+
+// useKeyword makes search_keyword (SEARCH_KEYWORD), a single query that may contain commas,
+// the only query when it was set with higher precedence than queries
+func (c *Config) useKeyword() {
+	if precedence(c.Sources["search_keyword"]) > precedence(c.Sources["queries"]) {
+		c.SearchQueries = []string{c.SearchKeyword}
+		c.Sources["queries"] = c.Sources["search_keyword"]
+	}
+}
 
-        // Search Settings with defaults
-        SearchKeyword:  getEnvOrDefault("SEARCH_KEYWORD", "Software Engineer"),
-        MaxPages:       getEnvAsInt("MAX_PAGES_TO_SCRAPE", 3),
+// precedence ranks a setting source: default, then the config file, then the environment
+func precedence(source string) int {
+	switch {
+	case source == "default":
+		return 0
+	case strings.HasPrefix(source, "env "):
+		return 2
+	}
+	return 1
+}
+
+// openSecrets unlocks the secrets file, if one is configured, and takes the password from it
+// unless the config file or environment already set one
+func (c *Config) openSecrets() []string {
+	if c.SecretsFile == "" {
+		return nil
+	}
+	passphrase, err := secrets.Passphrase(os.Getenv("SECRETS_PASSPHRASE"), c.SecretsKeyFile)
+	if err != nil {
+		return []string{c.source("secrets_file") + ": " + err.Error()}
+	}
+	store, err := secrets.Open(c.SecretsFile, passphrase)
+	if err != nil {
+		return []string{c.source("secrets_file") + ": " + err.Error()}
+	}
+	c.Secrets = store
+	if c.Password == "" && store.Password() != "" {
+		c.Password = store.Password()
+		c.Sources["password"] = "secrets file " + c.SecretsFile
+	}
+	return nil
+}
 
-        // Stealth & Human Behavior with defaults
-        DelayFactor: getEnvAsFloat("DELAY_FACTOR", 1.0),
-        ScrollMin:   getEnvAsInt("SCROLL_COUNT_MIN", 3),
-        ScrollMax:   getEnvAsInt("SCROLL_COUNT_MAX", 7),
+// defaultConfig returns the built-in defaults
+func defaultConfig() *Config {
+	return &Config{
+		Endpoints: DefaultEndpoints(),
 
-        // Safety Limits with defaults
-        InviteLimit: getEnvAsInt("DAILY_INVITE_LIMIT", 10),
-        SearchLimit: getEnvAsInt("DAILY_SEARCH_LIMIT", 50),
+		SearchQueries: []string{"Software Engineer"},
+		MaxPages:      3,
 
-        // Working Hours with defaults
-        WorkStart: getEnvOrDefault("WORKING_HOURS_START", "09:00"),
-        WorkEnd:   getEnvOrDefault("WORKING_HOURS_END", "21:00"),
+		DelayFactor: 1.0,
+		ScrollMin:   3,
+		ScrollMax:   7,
 
-        // Message and Note Template
-		ConnectMessageTemplate:  getEnvOrDefault("CONNECT_MESSAGE_TEMPLATE", "Hi {firstName}, I noticed your profile and would love to connect!"),
-		FollowupMessageTemplate: getEnvOrDefault("FOLLOW_UP_MESSAGE_TEMPLATE", "Hi {firstName}, thanks for connecting! Great to meet you."),
+		InviteLimit:  10,
+		SearchLimit:  50,
+		MessageLimit: 30,
+		ViewLimit:    150,
 
-        // Execution Defaults
-        DefaultMode: getEnvOrDefault("DEFAULT_MODE", "demo"),
-    }
+		HourlyInviteLimit:  5,
+		WeeklyInviteLimit:  80,
+		HourlySearchLimit:  30,
+		WeeklySearchLimit:  250,
+		HourlyMessageLimit: 10,
+		WeeklyMessageLimit: 150,
+		HourlyViewLimit:    40,
+		WeeklyViewLimit:    800,
 
-    // Validate working hours format (basic check)
-    if !isValidTimeFormat(cfg.WorkStart) || !isValidTimeFormat(cfg.WorkEnd) {
-        return nil, errors.New("WORKING_HOURS_START and WORKING_HOURS_END must be in HH:MM format")
-    }
+		WorkStart: "09:00",
+		WorkEnd:   "21:00",
 
-    return cfg, nil
+		SessionExpiryWarning: "3d",
+
+		ConnectMessageTemplate:  `Hi {{.FirstName | default "there"}}, I noticed your profile and would love to connect!`,
+		FollowupMessageTemplate: `Hi {{.FirstName | default "there"}}, thanks for connecting! Great to meet you.`,
+
+		InboxSyncLimit: 20,
+
+		LogLevel:  "info",
+		LogFormat: logging.FormatText,
+
+		DefaultMode: "demo",
+		Campaign:    DefaultCampaign,
+
+		DaemonSteps:    []string{"search", "connect", "message"},
+		DaemonInterval: "1h",
+
+		BreakerCooldown:    "24h",
+		BreakerMaxFailures: 5,
+	}
 }
 
-// getEnvOrDefault returns the environment variable value or a default if not set
-func getEnvOrDefault(key, defaultValue string) string {
-    value := os.Getenv(key)
-    if value == "" {
-        return defaultValue
-    }
-    return value
+// validate checks every scalar setting, returning one problem per invalid field
+func (c *Config) validate() []string {
+	var problems []string
+	add := func(key, format string, args ...any) {
+		problems = append(problems, c.source(key)+": "+fmt.Sprintf(format, args...))
+	}
+
+	// Older versions read campaigns from a separate JSON file
+	if os.Getenv("CAMPAIGNS_FILE") != "" {
+		problems = append(problems, "CAMPAIGNS_FILE is no longer read: move its campaigns into the campaigns section of the config file (CONFIG_FILE)")
+	}
+	if c.Email == "" {
+		add("email", "must be set (LINKEDIN_EMAIL)")
+	}
+	if c.Password == "" {
+		add("password", "must be set (LINKEDIN_PASSWORD, or stored in SECRETS_FILE with -mode=secrets import)")
+	}
+
+	c.Endpoints.BaseURL = strings.TrimRight(c.Endpoints.BaseURL, "/")
+	if _, err := url.ParseRequestURI(c.Endpoints.BaseURL); err != nil {
+		add("base_url", "%q must be an absolute URL (e.g. https://www.linkedin.com)", c.Endpoints.BaseURL)
+	}
+	paths := []struct{ key, path string }{
+		{"login_path", c.Endpoints.LoginPath},
+		{"feed_path", c.Endpoints.FeedPath},
+		{"profile_path", c.Endpoints.ProfilePath},
+		{"messaging_path", c.Endpoints.MessagingPath},
+	}
+	for _, p := range paths {
+		if !strings.HasPrefix(p.path, "/") {
+			add(p.key, "%q must start with /", p.path)
+		}
+	}
+
+	if len(c.SearchQueries) == 0 {
+		add("queries", "must list at least one search query")
+	}
+	for i, q := range c.SearchQueries {
+		if strings.TrimSpace(q) == "" {
+			add("queries", "query %d is empty", i+1)
+		}
+	}
+	if len(c.SearchQueries) > 0 && c.SearchKeyword != c.SearchQueries[0] {
+		c.SearchKeyword = c.SearchQueries[0]
+		c.setSource("search_keyword", c.Sources["queries"])
+	}
+	if c.MaxPages < 1 {
+		add("max_pages", "must be at least 1, got %d", c.MaxPages)
+	}
+
+	if c.DelayFactor <= 0 {
+		add("delay_factor", "must be greater than 0, got %g", c.DelayFactor)
+	}
+	if c.ScrollMin < 0 {
+		add("scroll_count_min", "must not be negative, got %d", c.ScrollMin)
+	}
+	if c.ScrollMax < c.ScrollMin {
+		add("scroll_count_max", "must not be below scroll_count_min (%d), got %d", c.ScrollMin, c.ScrollMax)
+	}
+
+	// 0 allows no actions of that kind
+	limits := []struct {
+		key   string
+		value int
+	}{
+		{"hourly_invite_limit", c.HourlyInviteLimit}, {"daily_invite_limit", c.InviteLimit}, {"weekly_invite_limit", c.WeeklyInviteLimit},
+		{"hourly_search_limit", c.HourlySearchLimit}, {"daily_search_limit", c.SearchLimit}, {"weekly_search_limit", c.WeeklySearchLimit},
+		{"hourly_message_limit", c.HourlyMessageLimit}, {"daily_message_limit", c.MessageLimit}, {"weekly_message_limit", c.WeeklyMessageLimit},
+		{"hourly_view_limit", c.HourlyViewLimit}, {"daily_view_limit", c.ViewLimit}, {"weekly_view_limit", c.WeeklyViewLimit},
+	}
+	for _, l := range limits {
+		if l.value < 0 {
+			add(l.key, "must not be negative, got %d", l.value)
+		}
+	}
+	if !isValidTimeFormat(c.WorkStart) {
+		add("working_hours_start", "%q must be in HH:MM format", c.WorkStart)
+	}
+	if !isValidTimeFormat(c.WorkEnd) {
+		add("working_hours_end", "%q must be in HH:MM format", c.WorkEnd)
+	}
+
+	window, err := parseDelay(c.SessionExpiryWarning)
+	if err != nil {
+		add("session_expiry_warning", "%q must be a duration like \"72h\" or \"3d\"", c.SessionExpiryWarning)
+	}
+	c.SessionWarnWindow = window
+
+	// A follow-up template from the environment overrides steps from the file; both in one place is ambiguous
+	if len(c.FollowupSteps) > 0 {
+		switch src := c.Sources["followup_template"]; {
+		case src == c.Sources["followup_steps"]:
+			add("followup_steps", "set either followup_template or followup_steps, not both")
+		case strings.HasPrefix(src, "env "):
+			c.FollowupSteps = nil
+			c.Sources["followup_steps"] = "overridden by " + src
+		}
+	}
+	if c.InboxSyncLimit < 1 {
+		add("inbox_sync_limit", "must be at least 1, got %d", c.InboxSyncLimit)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		add("log_level", "%v", err)
+	}
+	if c.LogFormat != logging.FormatText && c.LogFormat != logging.FormatJSON {
+		add("log_format", "%q must be %q or %q", c.LogFormat, logging.FormatText, logging.FormatJSON)
+	}
+	if c.DefaultMode == "" {
+		add("default_mode", "must not be empty")
+	}
+	if len(c.DaemonSteps) == 0 {
+		add("daemon_steps", "must list at least one of %s", strings.Join(DaemonModes, ", "))
+	}
+	for _, step := range c.DaemonSteps {
+		if !slices.Contains(DaemonModes, step) {
+			add("daemon_steps", "%q is not one of %s", step, strings.Join(DaemonModes, ", "))
+		}
+	}
+	wait, err := parseDelay(c.DaemonInterval)
+	if err != nil || wait <= 0 {
+		add("daemon_interval", "%q must be a positive duration like \"30m\" or \"2h\"", c.DaemonInterval)
+	}
+	c.DaemonWait = wait
+
+	cooldown, err := parseDelay(c.BreakerCooldown)
+	if err != nil || cooldown <= 0 {
+		add("breaker_cooldown", "%q must be a positive duration like \"12h\" or \"2d\"", c.BreakerCooldown)
+	}
+	c.BreakerWait = cooldown
+	if c.BreakerMaxFailures < 0 {
+		add("breaker_max_failures", "must not be negative, got %d", c.BreakerMaxFailures)
+	}
+	return problems
+}
+
+// loadFiles loads what the settings point at (selectors, templates and opt-out rules) and checks the campaigns
+func (c *Config) loadFiles() []string {
+	var problems []string
+
+	reg, err := selectors.Load(c.SelectorsFile)
+	if err != nil {
+		problems = append(problems, c.source("selectors_file")+": "+err.Error())
+	}
+	c.Selectors = reg
+
+	if err := loadTemplates(c); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, problem := range validateCampaigns(c.Campaigns) {
+		problems = append(problems, c.source("campaigns")+": "+problem)
+	}
+
+	classifier, err := optout.Load(c.OptOutFile)
+	if err != nil {
+		problems = append(problems, c.source("opt_out_file")+": "+err.Error())
+	}
+	c.OptOut = classifier
+
+	problems = append(problems, c.loadSchedule()...)
+
+	return problems
 }
 
-// getEnvAsInt returns the environment variable as an integer or a default if not set/invalid
-func getEnvAsInt(key string, defaultValue int) int {
-    valueStr := os.Getenv(key)
-    if valueStr == "" {
-        return defaultValue
-    }
+// loadSchedule builds c.Schedule from the timezone, weekly windows (or the working hours) and holidays
+func (c *Config) loadSchedule() []string {
+	var problems []string
+	loc, err := guard.LoadLocation(c.Timezone)
+	if err != nil {
+		problems = append(problems, c.source("timezone")+": "+err.Error())
+		loc = time.Local
+	}
+	schedule := guard.NewSchedule(loc)
 
-    value, err := strconv.Atoi(valueStr)
-    if err != nil {
-        return defaultValue
-    }
+	// Invalid working hours are already reported by validate
+	if len(c.ScheduleDays) == 0 && isValidTimeFormat(c.WorkStart) && isValidTimeFormat(c.WorkEnd) {
+		schedule.AddWindow("daily " + c.WorkStart + "-" + c.WorkEnd)
+	}
+	for _, entry := range c.ScheduleDays {
+		if err := schedule.AddWindow(entry); err != nil {
+			problems = append(problems, c.source("schedule")+": "+err.Error())
+		}
+	}
+	for _, entry := range c.Holidays {
+		if err := schedule.AddHoliday(entry); err != nil {
+			problems = append(problems, c.source("holidays")+": "+err.Error())
+		}
+	}
+	if c.HolidaysFile != "" {
+		if _, err := schedule.LoadICS(c.HolidaysFile); err != nil {
+			problems = append(problems, c.source("holidays_file")+": "+err.Error())
+		}
+	}
 
-    return value
+	c.Schedule = schedule
+	return problems
 }
 
-// getEnvAsFloat returns the environment variable as a float64 or a default if not set/invalid
-func getEnvAsFloat(key string, defaultValue float64) float64 {
-    valueStr := os.Getenv(key)
-    if valueStr == "" {
-        return defaultValue
-    }
+// Budgets returns the hourly, daily and 7-day ceilings for each kind of action guard.Budget enforces.
+// Search limits count profiles collected, not result pages.
+func (c *Config) Budgets() map[storage.ActionKind]guard.Limits {
+	return map[storage.ActionKind]guard.Limits{
+		storage.ActionSearchPage:  {Hourly: c.HourlySearchLimit, Daily: c.SearchLimit, Weekly: c.WeeklySearchLimit, CampaignDaily: c.CampaignSearchLimit},
+		storage.ActionProfileView: {Hourly: c.HourlyViewLimit, Daily: c.ViewLimit, Weekly: c.WeeklyViewLimit},
+		storage.ActionInvite:      {Hourly: c.HourlyInviteLimit, Daily: c.InviteLimit, Weekly: c.WeeklyInviteLimit, CampaignDaily: c.CampaignInviteLimit},
+		storage.ActionMessage:     {Hourly: c.HourlyMessageLimit, Daily: c.MessageLimit, Weekly: c.WeeklyMessageLimit},
+	}
+}
+
+// loadTemplates parses and validates the message templates, and the follow-up steps if set.
+// Connect notes must fit the site's note limit even for a long sample profile.
+func loadTemplates(cfg *Config) error {
+	connect, err := template.Parse("CONNECT_MESSAGE_TEMPLATE", cfg.ConnectMessageTemplate)
+	if err != nil {
+		return err
+	}
+	if err := connect.CheckLength(template.ConnectNoteLimit); err != nil {
+		return err
+	}
 
-    value, err := strconv.ParseFloat(valueStr, 64)
-    if err != nil {
-        return defaultValue
-    }
+	followup, err := template.Parse("FOLLOW_UP_MESSAGE_TEMPLATE", cfg.FollowupMessageTemplate)
+	if err != nil {
+		return err
+	}
+	if _, err := followup.Render(template.Data{}); err != nil {
+		return err
+	}
 
-    return value
+	cfg.ConnectTemplate, cfg.FollowupTemplate = connect, followup
+	cfg.Sequence = []FollowupStep{{Template: followup}}
+	if len(cfg.FollowupSteps) > 0 {
+		steps, problems := parseSteps(cfg.Sources["followup_steps"], cfg.FollowupSteps)
+		if len(problems) > 0 {
+			return errors.New(strings.Join(problems, "; "))
+		}
+		cfg.Sequence = steps
+	}
+	return nil
 }
 
 // isValidTimeFormat checks if a time string is in HH:MM format
 func isValidTimeFormat(timeStr string) bool {
-    if len(timeStr) != 5 {
-        return false
-    }
-    if timeStr[2] != ':' {
-        return false
-    }
-    // Check if HH and MM are numbers
-    hour, err1 := strconv.Atoi(timeStr[0:2])
-    minute, err2 := strconv.Atoi(timeStr[3:5])
-    if err1 != nil || err2 != nil {
-        return false
-    }
-    // Validate ranges
-    return hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59
-}
\ No newline at end of file
+	if len(timeStr) != 5 {
+		return false
+	}
+	if timeStr[2] != ':' {
+		return false
+	}
+	// Check if HH and MM are numbers
+	hour, err1 := strconv.Atoi(timeStr[0:2])
+	minute, err2 := strconv.Atoi(timeStr[3:5])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	// Validate ranges
+	return hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadWith runs Load in an empty directory with only the given environment and, unless file is
+// empty, a config file with that content. The credentials default to valid ones.
+func loadWith(t *testing.T, env map[string]string, file string) (*Config, error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	for _, s := range settings(&Config{}) {
+		if s.env != "" {
+			t.Setenv(s.env, "")
+		}
+	}
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("LINKEDIN_EMAIL", "test@example.com")
+	t.Setenv("LINKEDIN_PASSWORD", "password")
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+	if file != "" {
+		if err := os.WriteFile("config.yaml", []byte(file), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("CONFIG_FILE", "config.yaml")
+	}
+	return Load()
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := loadWith(t, nil, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !slices.Equal(cfg.SearchQueries, []string{"Software Engineer"}) || cfg.SearchKeyword != "Software Engineer" {
+		t.Errorf("queries = %q, keyword %q", cfg.SearchQueries, cfg.SearchKeyword)
+	}
+	if cfg.InviteLimit != 10 || cfg.BreakerWait != 24*time.Hour || cfg.SessionWarnWindow != 72*time.Hour {
+		t.Errorf("invite limit %d, breaker wait %s, session warning %s", cfg.InviteLimit, cfg.BreakerWait, cfg.SessionWarnWindow)
+	}
+	if src := cfg.Sources["max_pages"]; src != "default" {
+		t.Errorf("max_pages source = %q, want default", src)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	file := `
+# Comments are allowed
+queries:
+  - Go Developer
+  - Engineer, Berlin
+max_pages: 2
+daily_invite_limit: 7
+delay_factor: 1.5
+headless: true
+daemon_steps: [search, connect]
+followup_steps:
+  - delay: 0h
+    template: Hi {{.FirstName}}
+  - delay: 3d
+    template: Still there, {{.FirstName}}?
+`
+	cfg, err := loadWith(t, map[string]string{"DAILY_INVITE_LIMIT": "4"}, file)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !slices.Equal(cfg.SearchQueries, []string{"Go Developer", "Engineer, Berlin"}) {
+		t.Errorf("queries = %q", cfg.SearchQueries)
+	}
+	if cfg.MaxPages != 2 || cfg.DelayFactor != 1.5 || !cfg.Headless {
+		t.Errorf("max_pages %d, delay_factor %g, headless %v", cfg.MaxPages, cfg.DelayFactor, cfg.Headless)
+	}
+	if !slices.Equal(cfg.DaemonSteps, []string{"search", "connect"}) {
+		t.Errorf("daemon_steps = %q", cfg.DaemonSteps)
+	}
+	if len(cfg.Sequence) != 2 || cfg.Sequence[1].Delay != 72*time.Hour {
+		t.Errorf("sequence = %+v", cfg.Sequence)
+	}
+
+	// The environment overrides the file
+	if cfg.InviteLimit != 4 {
+		t.Errorf("daily_invite_limit = %d, want 4 from the environment", cfg.InviteLimit)
+	}
+	sources := map[string]string{
+		"daily_invite_limit": "env DAILY_INVITE_LIMIT",
+		"max_pages":          "config.yaml",
+		"scroll_count_min":   "default",
+	}
+	for key, want := range sources {
+		if got := cfg.Sources[key]; got != want {
+			t.Errorf("%s source = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		file string
+		want []string // Every one must be reported
+	}{
+		{
+			name: "malformed environment values",
+			env:  map[string]string{"DAILY_INVITE_LIMIT": "ten", "DELAY_FACTOR": "fast", "HEADLESS": "maybe"},
+			want: []string{
+				`daily_invite_limit (env DAILY_INVITE_LIMIT): "ten" is not a whole number`,
+				`delay_factor (env DELAY_FACTOR): "fast" is not a number`,
+				`headless (env HEADLESS): "maybe" is not true or false`,
+			},
+		},
+		{
+			name: "invalid values",
+			env:  map[string]string{"MAX_PAGES_TO_SCRAPE": "0", "WORKING_HOURS_START": "9am", "LOG_FORMAT": "xml", "BREAKER_COOLDOWN": "soon", "INBOX_SYNC_LIMIT": "0"},
+			want: []string{
+				"max_pages (env MAX_PAGES_TO_SCRAPE): must be at least 1",
+				`working_hours_start (env WORKING_HOURS_START): "9am" must be in HH:MM format`,
+				`log_format (env LOG_FORMAT): "xml"`,
+				`breaker_cooldown (env BREAKER_COOLDOWN): "soon"`,
+				"inbox_sync_limit (env INBOX_SYNC_LIMIT): must be at least 1",
+			},
+		},
+		{
+			name: "missing credentials",
+			env:  map[string]string{"LINKEDIN_EMAIL": "", "LINKEDIN_PASSWORD": ""},
+			want: []string{"email (default): must be set", "password (default): must be set"},
+		},
+		{
+			name: "config file types and keys",
+			file: "maxpages: 3\ndaily_invite_limit: ten\nfollowup_steps:\n  - delay: 1d\n    tempalte: x\n",
+			want: []string{
+				"maxpages (config.yaml line 1): unknown setting",
+				"daily_invite_limit (config.yaml): expected a whole number: line 2: cannot unmarshal !!str `ten` into int",
+				`followup_steps (config.yaml): expected a list of steps with delay and template: line 5: unknown step field "tempalte"`,
+			},
+		},
+		{
+			name: "campaign keys",
+			file: "campaigns:\n  - name: hiring\n    daily_invite_limt: 3\n",
+			want: []string{`campaigns (config.yaml): expected a list of campaigns: line 3: unknown campaign field "daily_invite_limt"`},
+		},
+		{
+			name: "campaign values",
+			file: "campaigns:\n  - name: Hiring\n    max_pages: -1\n  - name: events\n  - name: events\n",
+			want: []string{
+				`campaigns (config.yaml): campaign "Hiring": name must be lowercase letters, digits, '-' or '_'`,
+				`campaigns (config.yaml): campaign "Hiring": max_pages must not be negative`,
+				`campaigns (config.yaml): campaign "events": defined twice`,
+			},
+		},
+		{
+			name: "campaigns file from older versions",
+			env:  map[string]string{"CAMPAIGNS_FILE": "campaigns.json"},
+			want: []string{"CAMPAIGNS_FILE is no longer read"},
+		},
+		{
+			name: "config file syntax",
+			file: "queries: [unclosed\n",
+			want: []string{"invalid config file config.yaml"},
+		},
+		{
+			name: "negative limits",
+			file: "weekly_invite_limit: -1\nhourly_view_limit: -5\n",
+			want: []string{
+				"weekly_invite_limit (config.yaml): must not be negative, got -1",
+				"hourly_view_limit (config.yaml): must not be negative, got -5",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadWith(t, tt.env, tt.file)
+			if err == nil {
+				t.Fatal("Load succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not report %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		file       string
+		want       []string
+		wantSource string
+	}{
+		{"keyword is one query", map[string]string{"SEARCH_KEYWORD": "Engineer, Berlin"}, "", []string{"Engineer, Berlin"}, "env SEARCH_KEYWORD"},
+		{"queries split on |", map[string]string{"SEARCH_QUERIES": "Engineer, Berlin | Go Developer"}, "", []string{"Engineer, Berlin", "Go Developer"}, "env SEARCH_QUERIES"},
+		{"queries win over the keyword", map[string]string{"SEARCH_KEYWORD": "A", "SEARCH_QUERIES": "B|C"}, "", []string{"B", "C"}, "env SEARCH_QUERIES"},
+		{"keyword from env beats file queries", map[string]string{"SEARCH_KEYWORD": "A"}, "queries: [B, C]\n", []string{"A"}, "env SEARCH_KEYWORD"},
+		{"file queries", nil, "queries: [B, C]\n", []string{"B", "C"}, "config.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadWith(t, tt.env, tt.file)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !slices.Equal(cfg.SearchQueries, tt.want) {
+				t.Errorf("queries = %q, want %q", cfg.SearchQueries, tt.want)
+			}
+			if cfg.SearchKeyword != tt.want[0] {
+				t.Errorf("keyword = %q, want %q", cfg.SearchKeyword, tt.want[0])
+			}
+			if src := cfg.Sources["queries"]; src != tt.wantSource {
+				t.Errorf("queries source = %q, want %q", src, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestCampaignCaps(t *testing.T) {
+	file := `
+campaigns:
+  - name: hiring
+    queries: [Go Developer]
+    daily_invite_limit: 3
+  - name: events
+`
+	cfg, err := loadWith(t, map[string]string{"DAILY_INVITE_LIMIT": "20"}, file)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		campaign  string
+		wantCap   int // -1 for none
+		wantQuery string
+		wantDaily int
+	}{
+		{"hiring", 3, "Go Developer", 20},
+		{"events", -1, "Software Engineer", 20},
+		{"default", -1, "Software Engineer", 20},
+	}
+	for _, tt := range tests {
+		c := *cfg
+		if err := c.UseCampaign(tt.campaign); err != nil {
+			t.Fatalf("UseCampaign(%s): %v", tt.campaign, err)
+		}
+		gotCap := -1
+		if c.CampaignInviteLimit != nil {
+			gotCap = *c.CampaignInviteLimit
+		}
+		if gotCap != tt.wantCap || c.SearchQueries[0] != tt.wantQuery || c.InviteLimit != tt.wantDaily {
+			t.Errorf("%s: cap %d, query %q, daily %d; want %d, %q, %d",
+				tt.campaign, gotCap, c.SearchQueries[0], c.InviteLimit, tt.wantCap, tt.wantQuery, tt.wantDaily)
+		}
+	}
+	if err := cfg.UseCampaign("unknown"); err == nil {
+		t.Error("UseCampaign(unknown): no error")
+	}
+}
+
+func TestSetFromString(t *testing.T) {
+	var (
+		s     string
+		n     int
+		f     float64
+		b     bool
+		list  []string
+		steps []StepConfig
+	)
+	tests := []struct {
+		value   any
+		raw     string
+		sep     string
+		wantErr bool
+	}{
+		{&s, "  kept as is ", "", false},
+		{&n, " 42 ", "", false},
+		{&n, "4.5", "", true},
+		{&f, "0.5", "", false},
+		{&f, "half", "", true},
+		{&b, "true", "", false},
+		{&b, "yes", "", true},
+		{&list, "a, b ,c", "", false},
+		{&steps, "0h", "", true},
+	}
+	for _, tt := range tests {
+		if err := setFromString(tt.value, tt.raw, tt.sep); (err != nil) != tt.wantErr {
+			t.Errorf("setFromString(%T, %q) error = %v, want error %v", tt.value, tt.raw, err, tt.wantErr)
+		}
+	}
+	if s != "  kept as is " || n != 42 || f != 0.5 || !b || !slices.Equal(list, []string{"a", "b", "c"}) {
+		t.Errorf("parsed %q, %d, %g, %v, %q", s, n, f, b, list)
+	}
+
+	if err := setFromString(&list, "a, b|c", "|"); err != nil || !slices.Equal(list, []string{"a, b", "c"}) {
+		t.Errorf("custom separator: %q, %v", list, err)
+	}
+}
+
+func TestParseDelay(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"90m", 90 * time.Minute, false},
+		{"36h", 36 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"1.5d", 0, true},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDelay(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDelay(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Endpoints describes the site the bot talks to and the paths it navigates.
+// Pointing BaseURL at a staging, mirror or fake host redirects every navigation
+// and URL check in internal/linkedin.
+type Endpoints struct {
+	BaseURL       string // Site root without trailing slash, e.g. https://www.linkedin.com
+	LoginPath     string // Login form, e.g. /login
+	FeedPath      string // Home feed shown after login, e.g. /feed/
+	ProfilePath   string // Path prefix of member profiles, e.g. /in/
+	MessagingPath string // Inbox with the conversation list, e.g. /messaging/
+}
+
+// DefaultEndpoints returns the endpoints of the real LinkedIn site.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		BaseURL:       "https://www.linkedin.com",
+		LoginPath:     "/login",
+		FeedPath:      "/feed/",
+		ProfilePath:   "/in/",
+		MessagingPath: "/messaging/",
+	}
+}
+
+// LoginURL returns the absolute URL of the login form.
+func (e Endpoints) LoginURL() string {
+	return e.BaseURL + e.LoginPath
+}
+
+// FeedURL returns the absolute URL of the home feed.
+func (e Endpoints) FeedURL() string {
+	return e.BaseURL + e.FeedPath
+}
+
+// MessagingURL returns the absolute URL of the inbox.
+func (e Endpoints) MessagingURL() string {
+	return e.BaseURL + e.MessagingPath
+}
+
+// IsMessagingURL reports whether rawURL points into the inbox (list or thread) on the configured host.
+func (e Endpoints) IsMessagingURL(rawURL string) bool {
+	return e.hasPathPrefix(rawURL, e.MessagingPath)
+}
+
+// IsFeedURL reports whether rawURL points at the feed on the configured host.
+func (e Endpoints) IsFeedURL(rawURL string) bool {
+	return e.hasPathPrefix(rawURL, strings.TrimRight(e.FeedPath, "/"))
+}
+
+// IsLoginURL reports whether rawURL points at the login form on the configured host.
+func (e Endpoints) IsLoginURL(rawURL string) bool {
+	return e.hasPathPrefix(rawURL, strings.TrimRight(e.LoginPath, "/"))
+}
+
+// IsProfileURL reports whether rawURL is a member profile on the configured host:
+// the profile path followed by a non-empty slug.
+func (e Endpoints) IsProfileURL(rawURL string) bool {
+	if !e.hasPathPrefix(rawURL, e.ProfilePath) {
+		return false
+	}
+	u, _ := url.Parse(rawURL) // hasPathPrefix parsed it already
+	slug, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(u.Path, e.ProfilePath), "/"), "/")
+	return slug != ""
+}
+
+// hasPathPrefix checks that rawURL is on the configured host and its path is prefix or lies under it.
+// A prefix without a trailing slash only matches whole segments, so /feed does not match /feedback.
+func (e Endpoints) hasPathPrefix(rawURL, prefix string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || !e.sameHost(u) {
+		return false
+	}
+	if u.Path == prefix || strings.HasSuffix(prefix, "/") && strings.HasPrefix(u.Path, prefix) {
+		return true
+	}
+	return strings.HasPrefix(u.Path, prefix+"/")
+}
+
+// sameHost compares hosts ignoring a leading "www." so linkedin.com and www.linkedin.com match
+func (e Endpoints) sameHost(u *url.URL) bool {
+	base, err := url.Parse(e.BaseURL)
+	if err != nil {
+		return false
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Host), "www.") ==
+		strings.TrimPrefix(strings.ToLower(base.Host), "www.")
+}
@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestEndpointsURLChecks(t *testing.T) {
+	e := DefaultEndpoints()
+	fake := Endpoints{BaseURL: "http://127.0.0.1:8090", LoginPath: "/login", FeedPath: "/feed/", ProfilePath: "/in/", MessagingPath: "/messaging/"}
+
+	tests := []struct {
+		name  string
+		check func(string) bool
+		url   string
+		want  bool
+	}{
+		{"feed", e.IsFeedURL, "https://www.linkedin.com/feed/", true},
+		{"feed without slash", e.IsFeedURL, "https://www.linkedin.com/feed", true},
+		{"feed sub-page", e.IsFeedURL, "https://www.linkedin.com/feed/update/123", true},
+		{"feed without www", e.IsFeedURL, "https://linkedin.com/feed/?trk=x", true},
+		{"feedback is not the feed", e.IsFeedURL, "https://www.linkedin.com/feedback", false},
+		{"feed on another host", e.IsFeedURL, "https://evil.example.com/feed/", false},
+		{"login", e.IsLoginURL, "https://www.linkedin.com/login?session_redirect=x", true},
+		{"login-help is not the login form", e.IsLoginURL, "https://www.linkedin.com/login-help", false},
+		{"checkpoint is not the login form", e.IsLoginURL, "https://www.linkedin.com/checkpoint/lg/login", false},
+		{"profile", e.IsProfileURL, "https://www.linkedin.com/in/jane-doe/", true},
+		{"profile without trailing slash", e.IsProfileURL, "https://www.linkedin.com/in/jane-doe", true},
+		{"profile prefix alone", e.IsProfileURL, "https://www.linkedin.com/in/", false},
+		{"profile prefix without slash", e.IsProfileURL, "https://www.linkedin.com/in", false},
+		{"profile with empty slug", e.IsProfileURL, "https://www.linkedin.com/in//", false},
+		{"company is not a profile", e.IsProfileURL, "https://www.linkedin.com/company/acme", false},
+		{"inbox", e.IsMessagingURL, "https://www.linkedin.com/messaging/thread/abc/", true},
+		{"messaging-settings is not the inbox", e.IsMessagingURL, "https://www.linkedin.com/messaging-settings", false},
+		{"fake feed", fake.IsFeedURL, "http://127.0.0.1:8090/feed/", true},
+		{"real feed on fake site", fake.IsFeedURL, "https://www.linkedin.com/feed/", false},
+		{"unparseable", e.IsFeedURL, "://", false},
+	}
+	for _, tt := range tests {
+		if got := tt.check(tt.url); got != tt.want {
+			t.Errorf("%s: check(%q) = %v, want %v", tt.name, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// FollowupStep is one message of a follow-up sequence.
+// The first step's Delay counts from the moment the invite is seen accepted,
+// every later one from the previous step.
+type FollowupStep struct {
+	Delay    time.Duration
+	Template *template.Template
+}
+
+// StepConfig is the campaigns and config file form of a FollowupStep.
+type StepConfig struct {
+	Delay    string `json:"delay" yaml:"delay"` // Go duration ("36h", "90m") or whole days ("3d"); empty means 0
+	Template string `json:"template" yaml:"template"`
+}
+
+// UnmarshalYAML decodes a step from the config file, rejecting keys other than delay and template.
+func (s *StepConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; key.Value != "delay" && key.Value != "template" {
+				return fmt.Errorf("line %d: unknown step field %q", key.Line, key.Value)
+			}
+		}
+	}
+	type plain StepConfig // Without this method, so Decode does not recurse
+	return node.Decode((*plain)(s))
+}
+
+// parseSteps validates a campaign's follow-up steps, returning every problem found
+func parseSteps(where string, steps []StepConfig) ([]FollowupStep, []string) {
+	var parsed []FollowupStep
+	var problems []string
+	for i, s := range steps {
+		name := fmt.Sprintf("%s followup_steps[%d]", where, i)
+		delay, err := parseDelay(s.Delay)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+		if strings.TrimSpace(s.Template) == "" {
+			problems = append(problems, name+": template is empty")
+			continue
+		}
+		t, err := template.Parse(name, s.Template)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		parsed = append(parsed, FollowupStep{Delay: delay, Template: t})
+	}
+	return parsed, problems
+}
+
+// parseDelay accepts a Go duration or a whole number of days such as "3d"
+func parseDelay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid delay %q (use e.g. \"72h\" or \"3d\")", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("delay %q must not be negative", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setting is one configuration value: its key in the config file, the environment variable
+// that overrides it, and the Config field it lands in.
+// Keys match a campaign's wherever the two overlap.
+type setting struct {
+	key    string // Config file key
+	env    string // Overriding environment variable; "" for file-only settings
+	secret bool   // Masked by config-check
+	sep    string // Separator of list items in the environment; "" for a comma
+	value  any    // Pointer into Config: *string, *int, *float64, *bool, *[]string, *[]StepConfig or *[]Campaign
+}
+
+// Resolved is one setting as config-check prints it.
+type Resolved struct {
+	Key    string
+	Env    string
+	Value  string // Masked for secrets
+	Source string // "default", the config file path, "env NAME" or the campaign that set it
+}
+
+// settings returns the table of every file/env setting, pointing into cfg
+func settings(cfg *Config) []setting {
+	return []setting{
+		{key: "email", env: "LINKEDIN_EMAIL", value: &cfg.Email},
+		{key: "password", env: "LINKEDIN_PASSWORD", secret: true, value: &cfg.Password},
+		{key: "secrets_file", env: "SECRETS_FILE", value: &cfg.SecretsFile},
+		{key: "secrets_key_file", env: "SECRETS_KEY_FILE", value: &cfg.SecretsKeyFile},
+		{key: "session_expiry_warning", env: "SESSION_EXPIRY_WARNING", value: &cfg.SessionExpiryWarning},
+
+		{key: "base_url", env: "LINKEDIN_BASE_URL", value: &cfg.Endpoints.BaseURL},
+		{key: "login_path", env: "LINKEDIN_LOGIN_PATH", value: &cfg.Endpoints.LoginPath},
+		{key: "feed_path", env: "LINKEDIN_FEED_PATH", value: &cfg.Endpoints.FeedPath},
+		{key: "profile_path", env: "LINKEDIN_PROFILE_PATH", value: &cfg.Endpoints.ProfilePath},
+		{key: "messaging_path", env: "LINKEDIN_MESSAGING_PATH", value: &cfg.Endpoints.MessagingPath},
+		{key: "headless", env: "HEADLESS", value: &cfg.Headless},
+		{key: "selectors_file", env: "SELECTORS_FILE", value: &cfg.SelectorsFile},
+		{key: "opt_out_file", env: "OPT_OUT_FILE", value: &cfg.OptOutFile},
+
+		{key: "queries", env: "SEARCH_QUERIES", sep: "|", value: &cfg.SearchQueries},
+		{key: "search_keyword", env: "SEARCH_KEYWORD", value: &cfg.SearchKeyword},
+		{key: "max_pages", env: "MAX_PAGES_TO_SCRAPE", value: &cfg.MaxPages},
+
+		{key: "delay_factor", env: "DELAY_FACTOR", value: &cfg.DelayFactor},
+		{key: "scroll_count_min", env: "SCROLL_COUNT_MIN", value: &cfg.ScrollMin},
+		{key: "scroll_count_max", env: "SCROLL_COUNT_MAX", value: &cfg.ScrollMax},
+
+		{key: "daily_invite_limit", env: "DAILY_INVITE_LIMIT", value: &cfg.InviteLimit},
+		{key: "daily_search_limit", env: "DAILY_SEARCH_LIMIT", value: &cfg.SearchLimit},
+		{key: "daily_message_limit", env: "DAILY_MESSAGE_LIMIT", value: &cfg.MessageLimit},
+		{key: "daily_view_limit", env: "DAILY_VIEW_LIMIT", value: &cfg.ViewLimit},
+		{key: "hourly_invite_limit", env: "HOURLY_INVITE_LIMIT", value: &cfg.HourlyInviteLimit},
+		{key: "weekly_invite_limit", env: "WEEKLY_INVITE_LIMIT", value: &cfg.WeeklyInviteLimit},
+		{key: "hourly_search_limit", env: "HOURLY_SEARCH_LIMIT", value: &cfg.HourlySearchLimit},
+		{key: "weekly_search_limit", env: "WEEKLY_SEARCH_LIMIT", value: &cfg.WeeklySearchLimit},
+		{key: "hourly_message_limit", env: "HOURLY_MESSAGE_LIMIT", value: &cfg.HourlyMessageLimit},
+		{key: "weekly_message_limit", env: "WEEKLY_MESSAGE_LIMIT", value: &cfg.WeeklyMessageLimit},
+		{key: "hourly_view_limit", env: "HOURLY_VIEW_LIMIT", value: &cfg.HourlyViewLimit},
+		{key: "weekly_view_limit", env: "WEEKLY_VIEW_LIMIT", value: &cfg.WeeklyViewLimit},
+		{key: "working_hours_start", env: "WORKING_HOURS_START", value: &cfg.WorkStart},
+		{key: "working_hours_end", env: "WORKING_HOURS_END", value: &cfg.WorkEnd},
+		{key: "timezone", env: "TIMEZONE", value: &cfg.Timezone},
+		{key: "schedule", env: "SCHEDULE", value: &cfg.ScheduleDays},
+		{key: "holidays", env: "HOLIDAYS", value: &cfg.Holidays},
+		{key: "holidays_file", env: "HOLIDAYS_FILE", value: &cfg.HolidaysFile},
+
+		{key: "connect_template", env: "CONNECT_MESSAGE_TEMPLATE", value: &cfg.ConnectMessageTemplate},
+		{key: "followup_template", env: "FOLLOW_UP_MESSAGE_TEMPLATE", value: &cfg.FollowupMessageTemplate},
+		{key: "followup_steps", value: &cfg.FollowupSteps},
+
+		{key: "inbox_sync_limit", env: "INBOX_SYNC_LIMIT", value: &cfg.InboxSyncLimit},
+
+		{key: "log_level", env: "LOG_LEVEL", value: &cfg.LogLevel},
+		{key: "log_format", env: "LOG_FORMAT", value: &cfg.LogFormat},
+
+		{key: "default_mode", env: "DEFAULT_MODE", value: &cfg.DefaultMode},
+		{key: "dry_run", env: "DRY_RUN", value: &cfg.DryRun},
+		{key: "campaign", env: "CAMPAIGN", value: &cfg.Campaign},
+		{key: "campaigns", value: &cfg.Campaigns},
+		{key: "daemon_steps", env: "DAEMON_STEPS", value: &cfg.DaemonSteps},
+		{key: "daemon_interval", env: "DAEMON_INTERVAL", value: &cfg.DaemonInterval},
+		{key: "breaker_cooldown", env: "BREAKER_COOLDOWN", value: &cfg.BreakerCooldown},
+		{key: "breaker_max_failures", env: "BREAKER_MAX_FAILURES", value: &cfg.BreakerMaxFailures},
+	}
+}
+
+// applyFile sets every key present in the YAML config file at path.
+// Unknown keys and values of the wrong type are reported, all of them.
+func applyFile(cfg *Config, table []setting, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read config file: %v", err)}
+	}
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return []string{fmt.Sprintf("invalid config file %s: %s", path, yamlError(err))}
+	}
+
+	known := make(map[string]setting, len(table))
+	for _, s := range table {
+		known[s.key] = s
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		s, ok := known[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s (%s line %d): unknown setting", key, path, raw[key].Line))
+			continue
+		}
+		// Decode into a fresh value so a failed decode leaves the default untouched
+		node := raw[key]
+		target := reflect.New(reflect.TypeOf(s.value).Elem())
+		if err := node.Decode(target.Interface()); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): expected %s: %s", key, path, kindOf(s.value), yamlError(err)))
+			continue
+		}
+		reflect.ValueOf(s.value).Elem().Set(target.Elem())
+		cfg.Sources[key] = path
+	}
+	return problems
+}
+
+// yamlError flattens a YAML decoding error to one line, keeping the line numbers
+func yamlError(err error) string {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		return strings.Join(typeErr.Errors, "; ")
+	}
+	return strings.TrimPrefix(err.Error(), "yaml: ")
+}
+
+// applyEnv overrides settings from non-empty environment variables
+func applyEnv(cfg *Config, table []setting) []string {
+	var problems []string
+	for _, s := range table {
+		if s.env == "" {
+			continue
+		}
+		raw := os.Getenv(s.env)
+		if raw == "" {
+			continue
+		}
+		if err := setFromString(s.value, raw, s.sep); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (env %s): %v", s.key, s.env, err))
+			continue
+		}
+		cfg.Sources[s.key] = "env " + s.env
+	}
+	return problems
+}
+
+// setFromString parses an environment value strictly into the setting's type.
+// List items are separated by sep, or by commas if sep is "".
+func setFromString(value any, raw, sep string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = raw
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		*v = n
+	case *float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		*v = f
+	case *bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		*v = b
+	case *[]string:
+		var items []string
+		if sep == "" {
+			sep = ","
+		}
+		for _, item := range strings.Split(raw, sep) {
+			items = append(items, strings.TrimSpace(item))
+		}
+		*v = items
+	default:
+		return fmt.Errorf("cannot be set from the environment")
+	}
+	return nil
+}
+
+// kindOf describes the type a setting expects in the config file
+func kindOf(value any) string {
+	switch value.(type) {
+	case *string:
+		return "a string"
+	case *int:
+		return "a whole number"
+	case *float64:
+		return "a number"
+	case *bool:
+		return "true or false"
+	case *[]string:
+		return "a list of strings"
+	case *[]StepConfig:
+		return "a list of steps with delay and template"
+	case *[]Campaign:
+		return "a list of campaigns"
+	}
+	return "a value"
+}
+
+// source describes where a setting's value came from, for error messages
+func (c *Config) source(key string) string {
+	return fmt.Sprintf("%s (%s)", key, c.Sources[key])
+}
+
+// setSource records that key was set by src (e.g. a campaign)
+func (c *Config) setSource(key, src string) {
+	if c.Sources != nil {
+		c.Sources[key] = src
+	}
+}
+
+// Resolved returns every setting with its final value and where it came from, secrets masked.
+func (c *Config) Resolved() []Resolved {
+	var out []Resolved
+	for _, s := range settings(c) {
+		out = append(out, Resolved{Key: s.key, Env: s.env, Value: formatValue(s), Source: c.Sources[s.key]})
+	}
+	return out
+}
+
+// formatValue renders a setting's value for config-check
+func formatValue(s setting) string {
+	switch v := s.value.(type) {
+	case *string:
+		if s.secret {
+			if *v == "" {
+				return "(empty)"
+			}
+			return "********"
+		}
+		return strconv.Quote(*v)
+	case *int:
+		return strconv.Itoa(*v)
+	case *float64:
+		return strconv.FormatFloat(*v, 'g', -1, 64)
+	case *bool:
+		return strconv.FormatBool(*v)
+	case *[]string:
+		return fmt.Sprintf("%q", *v)
+	case *[]StepConfig:
+		if len(*v) == 0 {
+			return "(none)"
+		}
+		delays := make([]string, len(*v))
+		for i, step := range *v {
+			delays[i] = step.Delay
+			if delays[i] == "" {
+				delays[i] = "0"
+			}
+		}
+		return fmt.Sprintf("%d step(s), delays %s", len(*v), strings.Join(delays, ", "))
+	case *[]Campaign:
+		if len(*v) == 0 {
+			return "(none)"
+		}
+		names := make([]string, len(*v))
+		for i, camp := range *v {
+			names[i] = camp.Name
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
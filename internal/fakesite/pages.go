@@ -0,0 +1,220 @@
+package fakesite
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// Page templates mimic only the markup the bot relies on (ids, roles, aria-labels, button texts).
+// Dialogs and chat boxes live in <template> tags so they only enter the DOM after a click,
+// just like on the real site.
+
+const layout = `{{define "nav"}}
+<nav id="global-nav">
+  <input class="search-global-typeahead__input" placeholder="Search" aria-label="Search"
+         onkeydown="if (event.key === 'Enter') { location.href = '/search/results/all/?keywords=' + encodeURIComponent(this.value); }">
+</nav>
+{{end}}`
+
+var loginPage = mustParse("login", `<!DOCTYPE html>
+<html><head><title>LinkedIn Login</title></head>
+<body>
+  <form method="post" action="/login" class="login__form">
+    {{with .}}{{with .Error}}<div id="error-for-password">{{.}}</div>{{end}}{{end}}
+    <input id="username" name="session_key" type="text">
+    <input id="password" name="session_password" type="password">
+    <div class="login__form_action_container"><button type="submit">Sign in</button></div>
+  </form>
+</body></html>`)
+
+var feedPage = mustParse("feed", `<!DOCTYPE html>
+<html><head><title>Feed | LinkedIn</title></head>
+<body>
+  {{template "nav"}}
+  <main style="min-height: 2000px;"><h2>Feed</h2></main>
+</body></html>`)
+
+var searchAllPage = mustParse("search-all", `<!DOCTYPE html>
+<html><head><title>Search | LinkedIn</title></head>
+<body>
+  {{template "nav"}}
+  <div class="search-reusables__filter-bar">
+    <button aria-pressed="false" data-keywords="{{.Keywords}}"
+            onclick="location.href = '/search/results/people/?keywords=' + encodeURIComponent(this.dataset.keywords) + '&page=1'">People</button>
+  </div>
+  <main style="min-height: 2000px;"><p>All results for {{.Keywords}}</p></main>
+</body></html>`)
+
+var searchPeoplePage = mustParse("search-people", `<!DOCTYPE html>
+<html><head><title>People | LinkedIn</title></head>
+<body>
+  {{template "nav"}}
+  <div class="search-reusables__filter-bar"><button aria-pressed="true">People</button></div>
+  <main style="min-height: 2000px;">
+    <ul class="reusable-search__entity-result-list">
+    {{range .Results}}
+      <li class="reusable-search__result-container">
+        <a class="app-aware-link" href="/in/{{.Slug}}/?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3A{{.Slug}}">
+          <span class="entity-result__title-text">{{.Name}}</span>
+        </a>
+        <div class="entity-result__primary-subtitle">{{.Headline}}</div>
+        <div class="entity-result__secondary-subtitle">{{.Location}}</div>
+      </li>
+    {{end}}
+    </ul>
+    <div class="artdeco-pagination">
+      <span>Page {{.Page}}</span>
+      {{if .Next}}<button aria-label="Next" data-href="{{.Next}}" onclick="location.href = this.dataset.href">Next</button>{{end}}
+    </div>
+  </main>
+</body></html>`)
+
+var profilePage = mustParse("profile", `<!DOCTYPE html>
+<html><head><title>{{.Name}} | LinkedIn</title></head>
+<body data-slug="{{.Slug}}">
+  {{template "nav"}}
+  <main style="min-height: 2000px;">
+    <h1>{{.Name}}</h1>
+    <div class="text-body-medium break-words">{{.Headline}}</div>
+    <span class="text-body-small inline t-black--light break-words">{{.Location}}</span>
+    <button aria-label="Current company: {{.Company}}">{{.Company}}</button>
+    <div class="pv-top-card-v2-ctas">
+    {{if eq .State "connect"}}
+      <button onclick="openInvite()">Connect</button>
+      <button>More</button>
+    {{else if eq .State "connect_more"}}
+      <button>Follow</button>
+      <button onclick="document.getElementById('more-menu').hidden = false">More</button>
+      <div id="more-menu" hidden>
+        <div role="menuitem" onclick="openInvite()">Connect</div>
+      </div>
+    {{else if eq .State "pending"}}
+      <button>Pending</button>
+    {{else if eq .State "connected"}}
+      <button onclick="openChat()">Message</button>
+    {{else if eq .State "locked"}}
+      <button onclick="openPremium()">Message<svg data-test-icon="lock-small" width="16" height="16"></svg></button>
+    {{else if eq .State "inmail"}}
+      <button aria-label="Send InMail to {{.Name}}" class="premium-inmail-button">InMail</button>
+    {{end}}
+    </div>
+  </main>
+
+  <template id="invite-dialog">
+    <div role="dialog" class="artdeco-modal">
+      <p>You can add a note to personalize your invitation.</p>
+      <div id="note-slot"></div>
+      <button onclick="addNote(this)">Add a note</button>
+      <button onclick="sendInvite()">Send</button>
+    </div>
+  </template>
+
+  <template id="chat-box">
+    <div class="msg-overlay-conversation-bubble">
+      <ul class="msg-s-message-list-content">
+      {{range .Thread}}
+        <li class="msg-s-event-listitem{{if not .FromMe}} msg-s-event-listitem--other{{end}}">
+          <p class="msg-s-event-listitem__body">{{.Text}}</p>
+        </li>
+      {{end}}
+      </ul>
+      <div role="textbox" contenteditable="true" aria-label="Write a message…" style="min-height: 40px;"></div>
+      <button type="submit" onclick="sendMessage()">Send</button>
+      <button aria-label="Close your conversation" onclick="this.parentElement.remove()">X</button>
+    </div>
+  </template>
+
+  <template id="premium-popup">
+    <div role="dialog" class="artdeco-modal">
+      <h2>Message with Premium</h2>
+      <button aria-label="Dismiss" onclick="this.parentElement.remove()">X</button>
+    </div>
+  </template>
+
+  <script>
+    function mount(id) {
+      document.body.appendChild(document.getElementById(id).content.cloneNode(true));
+    }
+    function post(path, data) {
+      data.slug = document.body.dataset.slug;
+      return fetch(path, { method: 'POST', body: new URLSearchParams(data) });
+    }
+    function openInvite() {
+      const menu = document.getElementById('more-menu');
+      if (menu) { menu.hidden = true; }
+      mount('invite-dialog');
+    }
+    function addNote(btn) {
+      document.getElementById('note-slot').innerHTML = '<textarea name="message" maxlength="300"></textarea>';
+      btn.remove();
+    }
+    function sendInvite() {
+      const note = document.querySelector('#note-slot textarea');
+      post('/api/invite', { note: note ? note.value : '' });
+      document.querySelector('div[role="dialog"]').remove();
+    }
+    function openChat() { mount('chat-box'); }
+    function openPremium() { mount('premium-popup'); }
+    function sendMessage() {
+      const box = document.querySelector('div[role="textbox"]');
+      post('/api/message', { text: box.innerText });
+      box.innerText = '';
+    }
+  </script>
+</body></html>`)
+
+var inboxPage = mustParse("inbox", `<!DOCTYPE html>
+<html><head><title>Messaging | LinkedIn</title></head>
+<body>
+  {{template "nav"}}
+  <main>
+    <ul class="msg-conversations-container__conversations-list">
+    {{range .Conversations}}
+      <li class="msg-conversation-listitem">
+        <a class="msg-conversation-listitem__link" href="/messaging/thread/{{.Slug}}/">
+          <h3 class="msg-conversation-listitem__participant-names">{{.Name}}</h3>
+        </a>
+      </li>
+    {{end}}
+    </ul>
+  </main>
+</body></html>`)
+
+var threadPage = mustParse("thread", `<!DOCTYPE html>
+<html><head><title>{{.Name}} | Messaging | LinkedIn</title></head>
+<body>
+  {{template "nav"}}
+  <main>
+    <div class="msg-entity-lockup">
+      <a class="msg-thread__link-to-profile" href="/in/{{.Slug}}/">{{.Name}}</a>
+    </div>
+    <ul class="msg-s-message-list-content">
+    {{$name := .Name}}
+    {{range .Thread}}
+      <li class="msg-s-message-list__event">
+        <span class="msg-s-message-group__name">{{if .FromMe}}You{{else}}{{$name}}{{end}}</span>
+        {{if .SentAt}}<time class="msg-s-message-group__timestamp" datetime="{{.SentAt}}">{{.SentAt}}</time>{{end}}
+        <div class="msg-s-event-listitem{{if not .FromMe}} msg-s-event-listitem--other{{end}}">
+          <p class="msg-s-event-listitem__body">{{.Text}}</p>
+        </div>
+      </li>
+    {{end}}
+    </ul>
+  </main>
+</body></html>`)
+
+// mustParse builds a page template that can use the shared layout blocks.
+func mustParse(name, body string) *template.Template {
+	return template.Must(template.Must(template.New(name).Parse(layout)).Parse(body))
+}
+
+// render executes tmpl and writes it as an HTML response
+func render(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		slog.Error("fakesite: failed to render", "template", tmpl.Name(), logging.Err(err))
+	}
+}
@@ -0,0 +1,349 @@
+package fakesite
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sessionCookie is the cookie name the fake site issues after a successful login.
+const sessionCookie = "li_at"
+
+// ProfileState describes which action buttons a fake profile page renders.
+type ProfileState string
+
+const (
+	StateConnect     ProfileState = "connect"      // Direct "Connect" button
+	StateConnectMore ProfileState = "connect_more" // "Connect" hidden behind the "More" dropdown
+	StatePending     ProfileState = "pending"      // Invite already sent ("Pending")
+	StateConnected   ProfileState = "connected"    // 1st degree, "Message" opens the chat box
+	StateLocked      ProfileState = "locked"       // "Message" button with the premium lock icon
+	StateInMail      ProfileState = "inmail"       // Only "Send InMail" is available
+)
+
+// Profile is a person served by the fake site at /in/<Slug>/.
+type Profile struct {
+	Slug     string
+	Name     string
+	Headline string
+	Company  string
+	Location string
+	State    ProfileState
+}
+
+// Invite records a connection request received by the fake site.
+type Invite struct {
+	Slug string
+	Note string
+}
+
+// Message records a chat message received by the fake site.
+type Message struct {
+	Slug string
+	Text string
+}
+
+// ThreadMessage is one message in the chat between the bot's account and a profile.
+type ThreadMessage struct {
+	FromMe bool // Sent by the bot's account; false for replies from the profile
+	Text   string
+	SentAt string // Timestamp rendered in the inbox thread (RFC 3339); may be empty
+}
+
+// Site is an in-memory imitation of the LinkedIn pages the bot interacts with.
+// It is safe for concurrent use.
+type Site struct {
+	Email    string
+	Password string
+	PageSize int
+
+	mu       sync.Mutex
+	profiles []*Profile
+	invites  []Invite
+	messages []Message
+	threads  map[string][]ThreadMessage
+}
+
+// New returns a Site with the given credentials and a default set of profiles
+// covering every ProfileState. Two connections have replied: one with an opt-out request,
+// one with an ordinary answer.
+func New(email, password string) *Site {
+	s := &Site{Email: email, Password: password, PageSize: 5, threads: make(map[string][]ThreadMessage)}
+	states := []ProfileState{StateConnect, StateConnectMore, StatePending, StateConnected, StateLocked, StateInMail}
+	for i := 0; i < 12; i++ {
+		state := states[i%len(states)]
+		s.AddProfile(Profile{
+			Slug:     fmt.Sprintf("test-user-%02d", i+1),
+			Name:     fmt.Sprintf("Test%02d User", i+1),
+			Headline: fmt.Sprintf("Software Engineer (%s) at Acme %d", state, i%3+1),
+			Company:  fmt.Sprintf("Acme %d", i%3+1),
+			Location: "Pune, Maharashtra, India",
+			State:    state,
+		})
+	}
+	// One connection has already asked us to stop, to exercise opt-out detection
+	s.threads["test-user-10"] = []ThreadMessage{
+		{FromMe: true, Text: "Hi Test10, thanks for connecting! Great to meet you.", SentAt: "2024-05-02T09:15:00Z"},
+		{Text: "Please stop messaging me.", SentAt: "2024-05-02T11:40:00Z"},
+	}
+	// Another has answered normally, to exercise reply detection and inbox sync
+	s.threads["test-user-04"] = []ThreadMessage{
+		{FromMe: true, Text: "Hi Test04, thanks for connecting! Great to meet you.", SentAt: "2024-05-03T10:00:00Z"},
+		{Text: "Thanks! Happy to chat next week.", SentAt: "2024-05-03T16:20:00Z"},
+	}
+	return s
+}
+
+// NewServer starts an httptest server backed by site.
+// Callers must Close the returned server.
+func NewServer(site *Site) *httptest.Server {
+	return httptest.NewServer(site.Handler())
+}
+
+// AddProfile adds (or replaces) a profile on the site.
+func (s *Site) AddProfile(p Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.profiles {
+		if existing.Slug == p.Slug {
+			s.profiles[i] = &p
+			return
+		}
+	}
+	s.profiles = append(s.profiles, &p)
+}
+
+// SetState changes the state of an existing profile (e.g. to simulate an accepted invite).
+func (s *Site) SetState(slug string, state ProfileState) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.find(slug); p != nil {
+		p.State = state
+		return true
+	}
+	return false
+}
+
+// Profile returns a copy of the profile with the given slug.
+func (s *Site) Profile(slug string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.find(slug); p != nil {
+		return *p, true
+	}
+	return Profile{}, false
+}
+
+// Invites returns every connection request received so far.
+func (s *Site) Invites() []Invite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Invite(nil), s.invites...)
+}
+
+// Messages returns every chat message received so far.
+func (s *Site) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// AddReply appends a message from the profile to its chat thread (e.g. to simulate an opt-out reply).
+func (s *Site) AddReply(slug, text string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(slug) == nil {
+		return false
+	}
+	s.threads[slug] = append(s.threads[slug], ThreadMessage{Text: text})
+	return true
+}
+
+// Thread returns the chat thread with the profile, oldest first.
+func (s *Site) Thread(slug string) []ThreadMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ThreadMessage(nil), s.threads[slug]...)
+}
+
+// Handler returns the HTTP handler serving the fake site.
+func (s *Site) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleRoot)
+	mux.HandleFunc("/login", s.handleLogin)
+	mux.HandleFunc("/feed/", s.requireLogin(s.handleFeed))
+	mux.HandleFunc("/search/results/all/", s.requireLogin(s.handleSearchAll))
+	mux.HandleFunc("/search/results/people/", s.requireLogin(s.handleSearchPeople))
+	mux.HandleFunc("/in/", s.requireLogin(s.handleProfile))
+	mux.HandleFunc("/messaging/", s.requireLogin(s.handleInbox))
+	mux.HandleFunc("/messaging/thread/", s.requireLogin(s.handleThread))
+	mux.HandleFunc("/api/invite", s.requireLogin(s.handleInvite))
+	mux.HandleFunc("/api/message", s.requireLogin(s.handleMessage))
+	return mux
+}
+
+// find returns the profile with the given slug. Caller must hold s.mu.
+func (s *Site) find(slug string) *Profile {
+	for _, p := range s.profiles {
+		if p.Slug == slug {
+			return p
+		}
+	}
+	return nil
+}
+
+// requireLogin redirects to /login unless the session cookie is present
+func (s *Site) requireLogin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie(sessionCookie); err != nil || c.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Site) handleRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, "/feed/", http.StatusFound)
+}
+
+func (s *Site) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		if r.FormValue("session_key") == s.Email && r.FormValue("session_password") == s.Password {
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "fake-session", Path: "/", HttpOnly: true})
+			http.Redirect(w, r, "/feed/", http.StatusFound)
+			return
+		}
+		render(w, loginPage, map[string]any{"Error": "Wrong email or password."})
+		return
+	}
+	render(w, loginPage, nil)
+}
+
+func (s *Site) handleFeed(w http.ResponseWriter, r *http.Request) {
+	render(w, feedPage, nil)
+}
+
+func (s *Site) handleSearchAll(w http.ResponseWriter, r *http.Request) {
+	render(w, searchAllPage, map[string]any{"Keywords": r.URL.Query().Get("keywords")})
+}
+
+func (s *Site) handleSearchPeople(w http.ResponseWriter, r *http.Request) {
+	keywords := r.URL.Query().Get("keywords")
+	pageNum, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+
+	s.mu.Lock()
+	size := s.PageSize
+	if size < 1 {
+		size = 5
+	}
+	start := (pageNum - 1) * size
+	end := start + size
+	var results []Profile
+	for i := start; i < end && i < len(s.profiles); i++ {
+		results = append(results, *s.profiles[i])
+	}
+	hasNext := end < len(s.profiles)
+	s.mu.Unlock()
+
+	next := ""
+	if hasNext {
+		q := url.Values{"keywords": {keywords}, "page": {strconv.Itoa(pageNum + 1)}}
+		next = "/search/results/people/?" + q.Encode()
+	}
+
+	render(w, searchPeoplePage, map[string]any{
+		"Keywords": keywords,
+		"Page":     pageNum,
+		"Results":  results,
+		"Next":     next,
+	})
+}
+
+func (s *Site) handleProfile(w http.ResponseWriter, r *http.Request) {
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/in/"), "/")
+	p, ok := s.Profile(slug)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	render(w, profilePage, struct {
+		Profile
+		Thread []ThreadMessage
+	}{p, s.Thread(slug)})
+}
+
+func (s *Site) handleInbox(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	var conversations []Profile
+	for _, p := range s.profiles {
+		if len(s.threads[p.Slug]) > 0 {
+			conversations = append(conversations, *p)
+		}
+	}
+	s.mu.Unlock()
+	render(w, inboxPage, map[string]any{"Conversations": conversations})
+}
+
+func (s *Site) handleThread(w http.ResponseWriter, r *http.Request) {
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/messaging/thread/"), "/")
+	p, ok := s.Profile(slug)
+	thread := s.Thread(slug)
+	if !ok || len(thread) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	render(w, threadPage, struct {
+		Profile
+		Thread []ThreadMessage
+	}{p, thread})
+}
+
+func (s *Site) handleInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	slug := r.FormValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.find(slug)
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.invites = append(s.invites, Invite{Slug: slug, Note: r.FormValue("note")})
+	p.State = StatePending
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Site) handleMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	slug := r.FormValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.find(slug)
+	if p == nil || p.State != StateConnected {
+		http.Error(w, "not connected", http.StatusForbidden)
+		return
+	}
+	s.messages = append(s.messages, Message{Slug: slug, Text: r.FormValue("text")})
+	s.threads[slug] = append(s.threads[slug], ThreadMessage{FromMe: true, Text: r.FormValue("text")})
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,188 @@
+package fakesite
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// client talks to a fake site server without following redirects, optionally logged in
+type client struct {
+	t        *testing.T
+	base     string
+	loggedIn bool
+}
+
+// newClient starts a server for a default site
+func newClient(t *testing.T, loggedIn bool) (*Site, *client) {
+	t.Helper()
+	site := New("test@example.com", "password")
+	srv := NewServer(site)
+	t.Cleanup(srv.Close)
+	return site, &client{t: t, base: srv.URL, loggedIn: loggedIn}
+}
+
+// do sends a request, a form POST when form is not nil, and returns the response and its body
+func (c *client) do(method, path string, form url.Values) (*http.Response, string) {
+	c.t.Helper()
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.loggedIn {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: "fake-session"})
+	}
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func TestLogin(t *testing.T) {
+	_, c := newClient(t, false)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		form     url.Values
+		status   int
+		location string // Redirect target, if any
+		body     string // Must appear in the body, if set
+		cookie   bool   // Whether the session cookie is set
+	}{
+		{"login form", http.MethodGet, "/login", nil, http.StatusOK, "", `id="username"`, false},
+		{"feed needs login", http.MethodGet, "/feed/", nil, http.StatusFound, "/login", "", false},
+		{"profile needs login", http.MethodGet, "/in/test-user-01/", nil, http.StatusFound, "/login", "", false},
+		{"wrong password", http.MethodPost, "/login", url.Values{"session_key": {"test@example.com"}, "session_password": {"nope"}}, http.StatusOK, "", "Wrong email or password.", false},
+		{"right password", http.MethodPost, "/login", url.Values{"session_key": {"test@example.com"}, "session_password": {"password"}}, http.StatusFound, "/feed/", "", true},
+		{"root goes to the feed", http.MethodGet, "/", nil, http.StatusFound, "/feed/", "", false},
+	}
+	for _, tt := range tests {
+		resp, body := c.do(tt.method, tt.path, tt.form)
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+		if loc := resp.Header.Get("Location"); loc != tt.location {
+			t.Errorf("%s: redirect to %q, want %q", tt.name, loc, tt.location)
+		}
+		if tt.body != "" && !strings.Contains(body, tt.body) {
+			t.Errorf("%s: body does not contain %q", tt.name, tt.body)
+		}
+		cookie := false
+		for _, ck := range resp.Cookies() {
+			cookie = cookie || (ck.Name == sessionCookie && ck.Value != "")
+		}
+		if cookie != tt.cookie {
+			t.Errorf("%s: session cookie set = %v, want %v", tt.name, cookie, tt.cookie)
+		}
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	_, c := newClient(t, true)
+
+	// The default site has 12 profiles, 5 to a page
+	tests := []struct {
+		page    string
+		results int
+		next    string // Query of the Next button, empty on the last page
+	}{
+		{"1", 5, "keywords=Engineer&amp;page=2"},
+		{"2", 5, "keywords=Engineer&amp;page=3"},
+		{"3", 2, ""},
+		{"", 5, "keywords=Engineer&amp;page=2"}, // No page means the first
+	}
+	for _, tt := range tests {
+		resp, body := c.do(http.MethodGet, "/search/results/people/?keywords=Engineer&page="+tt.page, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("page %q: status %d", tt.page, resp.StatusCode)
+		}
+		if got := strings.Count(body, `class="app-aware-link"`); got != tt.results {
+			t.Errorf("page %q: %d results, want %d", tt.page, got, tt.results)
+		}
+		hasNext := strings.Contains(body, `aria-label="Next"`)
+		if hasNext != (tt.next != "") || (hasNext && !strings.Contains(body, tt.next)) {
+			t.Errorf("page %q: Next button %v, want one linking to %q", tt.page, hasNext, tt.next)
+		}
+	}
+}
+
+func TestInvite(t *testing.T) {
+	site, c := newClient(t, true)
+
+	tests := []struct {
+		name   string
+		method string
+		form   url.Values
+		status int
+	}{
+		{"invite with note", http.MethodPost, url.Values{"slug": {"test-user-01"}, "note": {"Hi Test01"}}, http.StatusNoContent},
+		{"invite without note", http.MethodPost, url.Values{"slug": {"test-user-02"}}, http.StatusNoContent},
+		{"unknown profile", http.MethodPost, url.Values{"slug": {"nobody"}}, http.StatusNotFound},
+		{"GET is refused", http.MethodGet, nil, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if resp, _ := c.do(tt.method, "/api/invite", tt.form); resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+
+	want := []Invite{{Slug: "test-user-01", Note: "Hi Test01"}, {Slug: "test-user-02"}}
+	if got := site.Invites(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Invites = %+v, want %+v", got, want)
+	}
+	if p, _ := site.Profile("test-user-01"); p.State != StatePending {
+		t.Errorf("invited profile is %s, want %s", p.State, StatePending)
+	}
+
+	c.loggedIn = false
+	if resp, _ := c.do(http.MethodPost, "/api/invite", url.Values{"slug": {"test-user-03"}}); resp.StatusCode != http.StatusFound {
+		t.Errorf("logged out: status %d, want a redirect to the login", resp.StatusCode)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	site, c := newClient(t, true)
+
+	tests := []struct {
+		name   string
+		slug   string
+		status int
+	}{
+		{"connection", "test-user-04", http.StatusNoContent},
+		{"not connected", "test-user-01", http.StatusForbidden},
+		{"locked message button", "test-user-05", http.StatusForbidden},
+		{"unknown profile", "nobody", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		resp, _ := c.do(http.MethodPost, "/api/message", url.Values{"slug": {tt.slug}, "text": {"Hello " + tt.slug}})
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+	if resp, _ := c.do(http.MethodGet, "/api/message", nil); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+
+	want := []Message{{Slug: "test-user-04", Text: "Hello test-user-04"}}
+	if got := site.Messages(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Messages = %+v, want %+v", got, want)
+	}
+}
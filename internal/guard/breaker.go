@@ -0,0 +1,136 @@
+package guard
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+)
+
+// ErrTripped is wrapped by every error from a tripped circuit breaker.
+var ErrTripped = errors.New("circuit breaker tripped")
+
+// Signal is a reason for the circuit breaker to trip.
+type Signal string
+
+const (
+	SignalInviteLimit Signal = "invite_limit" // The site's weekly invitation limit banner
+	SignalCheckpoint  Signal = "checkpoint"   // A security checkpoint or CAPTCHA
+	SignalRestricted  Signal = "restricted"   // A restricted-account notice
+	SignalFailures    Signal = "failures"     // Too many unexplained failures in a row
+)
+
+// Breaker stops every action once the site shows an account warning, or after too many
+// unexplained failures in a row, for a cooldown. Trips are kept in the database, so later
+// runs refuse to start until the cooldown has passed or the trip is cleared.
+type Breaker struct {
+	db          *sql.DB
+	cooldown    time.Duration
+	maxFailures int // 0 never trips on failures
+	failures    int // Unexplained failures in a row
+}
+
+// breaker is the breaker Proceed consults; nil lets every action through.
+var breaker *Breaker
+
+// NewBreaker returns a breaker that trips for cooldown, after maxFailures consecutive failures
+// (0 for never) or as soon as Trip is called.
+func NewBreaker(db *sql.DB, cooldown time.Duration, maxFailures int) *Breaker {
+	return &Breaker{db: db, cooldown: cooldown, maxFailures: maxFailures}
+}
+
+// SetBreaker installs b, consulted by Proceed before every action and used by Trip, Failure
+// and Success. nil removes it.
+func SetBreaker(b *Breaker) {
+	breaker = b
+}
+
+// Active returns the trip still cooling down, or nil if the breaker is closed.
+func (b *Breaker) Active() (*storage.BreakerTrip, error) {
+	return storage.GetActiveBreakerTrip(b.db, time.Now())
+}
+
+// Check returns an error wrapping ErrTripped, naming the signal and when the cooldown ends,
+// while a trip is active.
+func (b *Breaker) Check() error {
+	trip, err := b.Active()
+	if err != nil {
+		return fmt.Errorf("failed to check circuit breaker: %w", err)
+	}
+	if trip == nil {
+		return nil
+	}
+	return tripError(trip)
+}
+
+// Trip records signal, seen as described by detail, and stops every action for the cooldown.
+// It returns the error Check reports from now on.
+func (b *Breaker) Trip(signal Signal, detail string) error {
+	trip, err := storage.RecordBreakerTrip(b.db, string(signal), detail, time.Now().Add(b.cooldown))
+	if err != nil {
+		// Stop anyway: the warning was seen even if it could not be saved
+		logger.Error("❌ Failed to save circuit breaker trip", "signal", signal, logging.Err(err))
+		trip = &storage.BreakerTrip{Signal: string(signal), Detail: detail, TrippedAt: time.Now(), Until: time.Now().Add(b.cooldown)}
+	}
+	b.failures = 0
+	logger.Error("🚨 CIRCUIT BREAKER TRIPPED: stopping all actions",
+		"signal", signal,
+		"detail", detail,
+		"until", trip.Until.Format("2006-01-02 15:04"),
+		"cooldown", b.cooldown.String())
+	return tripError(trip)
+}
+
+// Failure counts an unexplained failure and trips the breaker once maxFailures happen in a row,
+// returning the trip's error.
+func (b *Breaker) Failure(err error) error {
+	b.failures++
+	logger.Debug("Consecutive failure", "count", b.failures, "max", b.maxFailures)
+	if b.maxFailures <= 0 || b.failures < b.maxFailures {
+		return nil
+	}
+	return b.Trip(SignalFailures, fmt.Sprintf("%d failures in a row, last: %v", b.failures, err))
+}
+
+// Success resets the count of consecutive failures.
+func (b *Breaker) Success() {
+	b.failures = 0
+}
+
+// Trip trips the installed breaker (see Breaker.Trip). The returned error also wraps ErrStopped,
+// so it ends the run like Proceed's, even when no breaker is installed.
+func Trip(signal Signal, detail string) error {
+	if breaker == nil {
+		logger.Error("🚨 Account warning: stopping", "signal", signal, "detail", detail)
+		return fmt.Errorf("%w: %w: %s (%s)", ErrStopped, ErrTripped, signal, detail)
+	}
+	return fmt.Errorf("%w: %w", ErrStopped, breaker.Trip(signal, detail))
+}
+
+// Failure counts an unexplained failure on the installed breaker (see Breaker.Failure).
+// The error, once it trips, also wraps ErrStopped.
+func Failure(err error) error {
+	if breaker == nil {
+		return nil
+	}
+	if err := breaker.Failure(err); err != nil {
+		return fmt.Errorf("%w: %w", ErrStopped, err)
+	}
+	return nil
+}
+
+// Success resets the installed breaker's count of consecutive failures.
+func Success() {
+	if breaker != nil {
+		breaker.Success()
+	}
+}
+
+// tripError describes an active trip
+func tripError(trip *storage.BreakerTrip) error {
+	return fmt.Errorf("%w: %s (%s) at %s, cooling down until %s", ErrTripped, trip.Signal, trip.Detail,
+		trip.TrippedAt.Local().Format("2006-01-02 15:04"), trip.Until.Local().Format("2006-01-02 15:04"))
+}
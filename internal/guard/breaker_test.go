@@ -0,0 +1,101 @@
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+)
+
+func TestBreakerFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxFailures int
+		outcomes    string // f for a failure, s for a success
+		tripped     bool
+	}{
+		{"trips after max in a row", 3, "fff", true},
+		{"below max", 3, "ff", false},
+		{"success resets the count", 3, "ffsff", false},
+		{"trips after a reset", 3, "fsfff", true},
+		{"zero never trips", 0, "ffffff", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(openTestDB(t), time.Hour, tt.maxFailures)
+			var err error
+			for i, outcome := range tt.outcomes {
+				if outcome == 's' {
+					b.Success()
+					continue
+				}
+				if err = b.Failure(fmt.Errorf("failure %d", i)); err != nil && i < len(tt.outcomes)-1 {
+					t.Fatalf("tripped early, at outcome %d: %v", i, err)
+				}
+			}
+			if tripped := errors.Is(err, ErrTripped); tripped != tt.tripped {
+				t.Fatalf("Failure = %v, want tripped %v", err, tt.tripped)
+			}
+			if check := b.Check(); errors.Is(check, ErrTripped) != tt.tripped {
+				t.Errorf("Check = %v, want tripped %v", check, tt.tripped)
+			}
+			if !tt.tripped {
+				return
+			}
+			trip, err := b.Active()
+			if err != nil || trip == nil || trip.Signal != string(SignalFailures) || !strings.Contains(trip.Detail, "last: failure") {
+				t.Errorf("Active = %+v, %v", trip, err)
+			}
+		})
+	}
+}
+
+func TestBreakerCooldown(t *testing.T) {
+	db := openTestDB(t)
+	b := NewBreaker(db, time.Hour, 0)
+	if _, err := storage.RecordBreakerTrip(db, string(SignalCheckpoint), "expired", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("RecordBreakerTrip: %v", err)
+	}
+	if err := b.Check(); err != nil {
+		t.Errorf("Check after the cooldown = %v", err)
+	}
+
+	err := b.Trip(SignalRestricted, "notice on the feed")
+	if !errors.Is(err, ErrTripped) || !strings.Contains(err.Error(), "restricted (notice on the feed)") {
+		t.Errorf("Trip = %v", err)
+	}
+	// A later run with a fresh breaker still sees the trip
+	if err := NewBreaker(db, time.Hour, 0).Check(); !errors.Is(err, ErrTripped) {
+		t.Errorf("Check during the cooldown = %v", err)
+	}
+	if _, err := storage.ClearBreakerTrips(db, time.Now()); err != nil {
+		t.Fatalf("ClearBreakerTrips: %v", err)
+	}
+	if err := b.Check(); err != nil {
+		t.Errorf("Check after clearing = %v", err)
+	}
+}
+
+func TestInstalledBreaker(t *testing.T) {
+	t.Cleanup(func() { SetBreaker(nil) })
+
+	SetBreaker(nil)
+	if err := Trip(SignalInviteLimit, "banner"); !errors.Is(err, ErrStopped) || !errors.Is(err, ErrTripped) {
+		t.Errorf("Trip without a breaker = %v, want ErrStopped and ErrTripped", err)
+	}
+	if err := Failure(errors.New("boom")); err != nil {
+		t.Errorf("Failure without a breaker = %v", err)
+	}
+
+	b := NewBreaker(openTestDB(t), time.Hour, 1)
+	SetBreaker(b)
+	if err := Failure(errors.New("boom")); !errors.Is(err, ErrStopped) || !errors.Is(err, ErrTripped) {
+		t.Errorf("Failure = %v, want ErrStopped and ErrTripped", err)
+	}
+	if err := b.Check(); !errors.Is(err, ErrTripped) {
+		t.Errorf("Check after the installed breaker tripped = %v", err)
+	}
+}
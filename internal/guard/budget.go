@@ -0,0 +1,137 @@
+package guard
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+)
+
+// ErrBudgetExhausted is wrapped by Reserve when an action would exceed one of its ceilings.
+var ErrBudgetExhausted = errors.New("budget exhausted")
+
+// Limits are the ceilings for one kind of action: within the last hour, since midnight and within
+// the last 7 days, counted across every campaign, plus an optional cap since midnight for the
+// campaign in use. For search pages they count the profiles collected, not the pages.
+type Limits struct {
+	Hourly        int
+	Daily         int
+	Weekly        int
+	CampaignDaily *int // nil when the campaign has no cap of its own
+}
+
+// Budget decides how many more actions of each kind may be sent, counting the actions ledger.
+// Kinds without limits are unlimited; a limit of 0 allows none.
+type Budget struct {
+	db     *sql.DB
+	limits map[storage.ActionKind]Limits
+	loc    *time.Location // Where the day starts at midnight
+}
+
+// budgetWindow is one of the periods a ceiling applies to
+type budgetWindow struct {
+	name     string
+	since    func(now time.Time) time.Time
+	limit    func(l Limits) (int, bool) // false if the window has no ceiling
+	campaign bool                       // Counts only the campaign in use
+}
+
+var budgetWindows = []budgetWindow{
+	{name: "in the last hour", since: func(now time.Time) time.Time { return now.Add(-time.Hour) }, limit: func(l Limits) (int, bool) { return l.Hourly, true }},
+	{name: "today", since: startOfDay, limit: func(l Limits) (int, bool) { return l.Daily, true }},
+	{name: "in the last 7 days", since: func(now time.Time) time.Time { return now.AddDate(0, 0, -7) }, limit: func(l Limits) (int, bool) { return l.Weekly, true }},
+	{name: "today for this campaign", since: startOfDay, campaign: true, limit: func(l Limits) (int, bool) {
+		if l.CampaignDaily == nil {
+			return 0, false
+		}
+		return *l.CampaignDaily, true
+	}},
+}
+
+// NewBudget returns a budget enforcing limits against the ledger in db. Days start at midnight in
+// loc, the schedule's timezone; nil means the host's.
+func NewBudget(db *sql.DB, limits map[storage.ActionKind]Limits, loc *time.Location) *Budget {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Budget{db: db, limits: limits, loc: loc}
+}
+
+// Limits returns the ceilings for kind and whether it has any.
+func (b *Budget) Limits(kind storage.ActionKind) (Limits, bool) {
+	l, ok := b.limits[kind]
+	return l, ok
+}
+
+// Remaining returns how many more kind actions fit in every window, 0 once one is full.
+// Kinds without limits report math.MaxInt.
+func (b *Budget) Remaining(kind storage.ActionKind) (int, error) {
+	limits, ok := b.limits[kind]
+	if !ok {
+		return math.MaxInt, nil
+	}
+	_, remaining, err := b.tightest(kind, limits, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return max(remaining, 0), nil
+}
+
+// Reserve checks that one more kind action fits every window before it is sent. If not, the error
+// wraps ErrBudgetExhausted and names the full window. The action is charged when it is recorded in
+// the ledger, so an action that is skipped after reserving costs nothing.
+func (b *Budget) Reserve(kind storage.ActionKind) error {
+	limits, ok := b.limits[kind]
+	if !ok {
+		return nil
+	}
+	w, remaining, err := b.tightest(kind, limits, time.Now())
+	if err != nil {
+		return err
+	}
+	if remaining <= 0 {
+		limit, _ := w.limit(limits)
+		return fmt.Errorf("%w: %s limit of %d %s reached", ErrBudgetExhausted, kind, limit, w.name)
+	}
+	logger.Debug("Budget reserved", "kind", kind, "remaining", remaining-1, "window", w.name)
+	return nil
+}
+
+// CountToday returns how many kind actions the ledger holds since midnight across every campaign
+// (for search pages, the profiles they added).
+func (b *Budget) CountToday(kind storage.ActionKind) (int, error) {
+	return storage.CountActions(b.db, kind, startOfDay(time.Now().In(b.loc)))
+}
+
+// startOfDay returns the midnight that began now's day, in now's location
+func startOfDay(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// tightest returns the window with the least room left for kind at now, and that room
+func (b *Budget) tightest(kind storage.ActionKind, limits Limits, now time.Time) (budgetWindow, int, error) {
+	now = now.In(b.loc)
+	var tightest budgetWindow
+	remaining := math.MaxInt
+	for _, w := range budgetWindows {
+		limit, ok := w.limit(limits)
+		if !ok {
+			continue
+		}
+		count := storage.CountActions
+		if w.campaign {
+			count = storage.CountCampaignActions
+		}
+		used, err := count(b.db, kind, w.since(now))
+		if err != nil {
+			return tightest, 0, err
+		}
+		if left := limit - used; left < remaining {
+			tightest, remaining = w, left
+		}
+	}
+	return tightest, remaining, nil
+}
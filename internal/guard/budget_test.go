@@ -0,0 +1,136 @@
+package guard
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+)
+
+// openTestDB creates a fresh database in a temporary working directory, using the default campaign
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	useCampaign(t, db, "default")
+	return db
+}
+
+// useCampaign scopes the ledger to the named campaign, failing the test on error
+func useCampaign(t *testing.T, db *sql.DB, name string) {
+	t.Helper()
+	if _, err := storage.UseCampaign(db, name); err != nil {
+		t.Fatalf("UseCampaign(%s): %v", name, err)
+	}
+}
+
+func TestBudgetReserve(t *testing.T) {
+	db := openTestDB(t)
+	// One invite for another campaign, then two for the campaign in use
+	useCampaign(t, db, "events")
+	for _, campaign := range []string{"events", "hiring", "hiring"} {
+		useCampaign(t, db, campaign)
+		if err := storage.RecordAction(db, storage.ActionInvite, "", 1, ""); err != nil {
+			t.Fatalf("RecordAction: %v", err)
+		}
+	}
+	capOf := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		limits    Limits
+		remaining int
+		fullMatch string // Empty while there is room
+	}{
+		{"room left", Limits{Hourly: 5, Daily: 10, Weekly: 20}, 2, ""},
+		{"daily is tightest", Limits{Hourly: 10, Daily: 4, Weekly: 20}, 1, ""},
+		{"hourly full", Limits{Hourly: 3, Daily: 10, Weekly: 20}, 0, "invite limit of 3 in the last hour reached"},
+		{"weekly over", Limits{Hourly: 10, Daily: 10, Weekly: 2}, 0, "invite limit of 2 in the last 7 days reached"},
+		{"zero allows none", Limits{Hourly: 0, Daily: 10, Weekly: 20}, 0, "invite limit of 0 in the last hour reached"},
+		{"campaign cap counts the campaign only", Limits{Hourly: 10, Daily: 10, Weekly: 20, CampaignDaily: capOf(3)}, 1, ""},
+		{"campaign cap full", Limits{Hourly: 10, Daily: 10, Weekly: 20, CampaignDaily: capOf(2)}, 0, "invite limit of 2 today for this campaign reached"},
+		{"account full before the campaign cap", Limits{Hourly: 10, Daily: 3, Weekly: 20, CampaignDaily: capOf(5)}, 0, "invite limit of 3 today reached"},
+	}
+	for _, tt := range tests {
+		b := NewBudget(db, map[storage.ActionKind]Limits{storage.ActionInvite: tt.limits}, nil)
+		remaining, err := b.Remaining(storage.ActionInvite)
+		if err != nil || remaining != tt.remaining {
+			t.Errorf("%s: Remaining = %d, %v; want %d", tt.name, remaining, err, tt.remaining)
+		}
+		err = b.Reserve(storage.ActionInvite)
+		if tt.fullMatch == "" {
+			if err != nil {
+				t.Errorf("%s: Reserve: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrBudgetExhausted) || !strings.Contains(err.Error(), tt.fullMatch) {
+			t.Errorf("%s: Reserve = %v, want ErrBudgetExhausted with %q", tt.name, err, tt.fullMatch)
+		}
+	}
+}
+
+func TestBudgetUnlimitedKind(t *testing.T) {
+	b := NewBudget(openTestDB(t), map[storage.ActionKind]Limits{storage.ActionInvite: {}}, nil)
+	if remaining, err := b.Remaining(storage.ActionMessage); err != nil || remaining != math.MaxInt {
+		t.Errorf("Remaining = %d, %v; want unlimited", remaining, err)
+	}
+	if err := b.Reserve(storage.ActionMessage); err != nil {
+		t.Errorf("Reserve: %v", err)
+	}
+	if _, ok := b.Limits(storage.ActionMessage); ok {
+		t.Error("Limits reports ceilings for an unlimited kind")
+	}
+}
+
+func TestBudgetDayStartsInLocation(t *testing.T) {
+	db := openTestDB(t)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 23:00 in Berlin on the 3rd is 22:00 UTC; half past midnight in Berlin is still the 3rd in UTC
+	sent := time.Date(2026, 3, 3, 23, 0, 0, 0, berlin)
+	now := time.Date(2026, 3, 4, 0, 30, 0, 0, berlin)
+	if _, err := db.Exec("INSERT INTO actions (kind, created_at) VALUES (?, ?)", storage.ActionInvite, sent.Local()); err != nil {
+		t.Fatal(err)
+	}
+	limits := Limits{Hourly: 10, Daily: 1, Weekly: 10}
+
+	tests := []struct {
+		loc  *time.Location
+		want int
+	}{
+		{berlin, 1},   // Sent yesterday
+		{time.UTC, 0}, // Sent today
+	}
+	for _, tt := range tests {
+		b := NewBudget(db, map[storage.ActionKind]Limits{storage.ActionInvite: limits}, tt.loc)
+		w, remaining, err := b.tightest(storage.ActionInvite, limits, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.loc, err)
+		}
+		if remaining != tt.want {
+			t.Errorf("%s: remaining = %d (window %q), want %d", tt.loc, remaining, w.name, tt.want)
+		}
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := startOfDay(time.Date(2026, 3, 4, 1, 0, 0, 0, time.UTC).In(tokyo))
+	if want := time.Date(2026, 3, 4, 0, 0, 0, 0, tokyo); !got.Equal(want) || got.Location() != tokyo {
+		t.Errorf("startOfDay = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStopped wraps the reason a run has to stop before its next action (see Proceed).
+var ErrStopped = errors.New("run stopped")
+
+// checkpoint decides whether the next action may start; nil lets every action through.
+var checkpoint func() error
+
+// SetCheckpoint installs fn, consulted by Proceed before every action. Daemon mode uses it to stop
+// at shutdown or when working hours end. nil removes it.
+func SetCheckpoint(fn func() error) {
+	checkpoint = fn
+}
+
+// Proceed returns an error wrapping ErrStopped if the run must stop before its next action:
+// the installed breaker is tripped (the error also wraps ErrTripped) or the checkpoint says so.
+func Proceed() error {
+	if breaker != nil {
+		if err := breaker.Check(); err != nil {
+			return fmt.Errorf("%w: %w", ErrStopped, err)
+		}
+	}
+	if checkpoint == nil {
+		return nil
+	}
+	if err := checkpoint(); err != nil {
+		return fmt.Errorf("%w: %v", ErrStopped, err)
+	}
+	return nil
+}
+
+// WaitForWorkingHours returns once the schedule allows the bot to run, sleeping until the next
+// window if needed. It returns ctx's error if ctx is cancelled first.
+func WaitForWorkingHours(ctx context.Context, schedule *Schedule) error {
+	next, err := schedule.Next(time.Now())
+	if err != nil {
+		return err
+	}
+	wait := time.Until(next)
+	if wait <= 0 {
+		return nil
+	}
+	logger.Info("😴 Outside working hours. Sleeping until the next window", "until", next.Format("Mon 2006-01-02 15:04 MST"), "wait", wait.Round(time.Minute).String())
+	return Wait(ctx, wait)
+}
+
+// Wait sleeps for d, returning ctx's error early if ctx is cancelled.
+func Wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
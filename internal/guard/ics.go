@@ -0,0 +1,145 @@
+package guard
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// icsEvent is the part of a VEVENT LoadICS needs
+type icsEvent struct {
+	summary    string
+	start, end string // DTSTART/DTEND property lines, parameters included
+}
+
+// LoadICS adds every event of a local iCalendar (.ics) file as a holiday, returning how many days
+// were added. All-day events cover each date up to their exclusive DTEND; timed events mark the date
+// they start on, in the schedule's timezone. Recurring events (RRULE) count on their first date only.
+func (s *Schedule) LoadICS(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	defer f.Close()
+
+	lines, err := unfoldICS(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+
+	added := 0
+	var ev *icsEvent
+	for _, line := range lines {
+		name, _, _ := strings.Cut(line, ":")
+		name, _, _ = strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(line, "BEGIN:VEVENT") {
+				ev = &icsEvent{}
+			}
+		case "SUMMARY":
+			if ev != nil {
+				_, ev.summary, _ = strings.Cut(line, ":")
+			}
+		case "DTSTART":
+			if ev != nil {
+				ev.start = line
+			}
+		case "DTEND":
+			if ev != nil {
+				ev.end = line
+			}
+		case "END":
+			if ev == nil || !strings.EqualFold(line, "END:VEVENT") {
+				continue
+			}
+			days, err := s.eventDays(ev)
+			if err != nil {
+				return added, fmt.Errorf("%s: event %q: %w", path, unescapeICS(ev.summary), err)
+			}
+			for _, day := range days {
+				if _, ok := s.holidays[day]; !ok {
+					added++
+				}
+				s.holidays[day] = unescapeICS(ev.summary)
+			}
+			ev = nil
+		}
+	}
+	return added, nil
+}
+
+// eventDays returns the dates (2006-01-02) an event covers
+func (s *Schedule) eventDays(ev *icsEvent) ([]string, error) {
+	if ev.start == "" {
+		return nil, fmt.Errorf("no DTSTART")
+	}
+	start, allDay, err := s.parseICSTime(ev.start)
+	if err != nil {
+		return nil, err
+	}
+	days := []string{start.Format("2006-01-02")}
+	if !allDay || ev.end == "" {
+		return days, nil
+	}
+	end, _, err := s.parseICSTime(ev.end)
+	if err != nil {
+		return nil, err
+	}
+	for d := start.AddDate(0, 0, 1); d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format("2006-01-02"))
+	}
+	return days, nil
+}
+
+// parseICSTime parses a DTSTART/DTEND line: a DATE (all day, in the schedule's zone), a UTC
+// DATE-TIME ending in Z, or a local DATE-TIME in its TZID (the schedule's zone if none)
+func (s *Schedule) parseICSTime(line string) (t time.Time, allDay bool, err error) {
+	params, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return t, false, fmt.Errorf("invalid property %q", line)
+	}
+	loc := s.loc
+	for _, p := range strings.Split(params, ";")[1:] {
+		if key, val, _ := strings.Cut(p, "="); strings.EqualFold(key, "TZID") {
+			if loc, err = time.LoadLocation(strings.Trim(val, `"`)); err != nil {
+				return t, false, fmt.Errorf("unknown TZID %q", val)
+			}
+		}
+	}
+	switch {
+	case len(value) == 8:
+		t, err = time.ParseInLocation("20060102", value, s.loc)
+		allDay = true
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return t, false, fmt.Errorf("invalid date %q", value)
+	}
+	return t.In(s.loc), allDay, nil
+}
+
+// unfoldICS reads content lines, joining folded continuation lines (RFC 5545 3.1)
+func unfoldICS(f *os.File) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// unescapeICS undoes TEXT value escaping
+func unescapeICS(text string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(strings.TrimSpace(text))
+}
@@ -0,0 +1,89 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeICS writes an iCalendar file with CRLF line endings, as calendar exports use
+func writeICS(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	content := strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...), "END:VCALENDAR"), "\r\n")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadICS(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeICS(t,
+		"BEGIN:VEVENT", "SUMMARY:Christmas Day", "DTSTART;VALUE=DATE:20261225", "DTEND;VALUE=DATE:20261226", "END:VEVENT",
+		"BEGIN:VEVENT", "SUMMARY:Company retreat\\, offsite", "DTSTART;VALUE=DATE:20260706", "DTEND;VALUE=DATE:20260709", "END:VEVENT",
+		// 23:30 UTC is already the next day in Berlin
+		"BEGIN:VEVENT", "SUMMARY:Late", "DTSTART:20260314T233000Z", "DTEND:20260315T003000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "SUMMARY:Tokyo meeting", "DTSTART;TZID=Asia/Tokyo:20260401T060000", "END:VEVENT",
+		"BEGIN:VEVENT", "SUMMARY:Folded", "  summary", "DTSTART;VALUE=DATE:20260501", "END:VEVENT",
+	)
+
+	s := NewSchedule(loc)
+	added, err := s.LoadICS(path)
+	if err != nil {
+		t.Fatalf("LoadICS: %v", err)
+	}
+	if added != 7 {
+		t.Errorf("added %d days, want 7", added)
+	}
+
+	tests := []struct {
+		date string
+		name string
+		want bool
+	}{
+		{"2026-12-25", "Christmas Day", true},
+		{"2026-12-26", "", false}, // DTEND is exclusive
+		{"2026-07-06", "Company retreat, offsite", true},
+		{"2026-07-08", "Company retreat, offsite", true},
+		{"2026-07-09", "", false},
+		{"2026-03-15", "Late", true},
+		{"2026-03-14", "", false},
+		{"2026-04-01", "Tokyo meeting", false}, // 06:00 in Tokyo is the previous evening in Berlin
+		{"2026-03-31", "Tokyo meeting", true},
+		{"2026-05-01", "Folded summary", true},
+	}
+	for _, tt := range tests {
+		day, _ := time.ParseInLocation("2006-01-02", tt.date, loc)
+		name, ok := s.holiday(day)
+		if ok != tt.want || (ok && name != tt.name) {
+			t.Errorf("%s: holiday = (%q, %v), want (%q, %v)", tt.date, name, ok, tt.name, tt.want)
+		}
+	}
+}
+
+func TestLoadICSErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"no start", []string{"BEGIN:VEVENT", "SUMMARY:Broken", "END:VEVENT"}, `event "Broken": no DTSTART`},
+		{"bad date", []string{"BEGIN:VEVENT", "SUMMARY:Broken", "DTSTART;VALUE=DATE:2026-12-25", "END:VEVENT"}, `invalid date "2026-12-25"`},
+		{"bad timezone", []string{"BEGIN:VEVENT", "SUMMARY:Broken", "DTSTART;TZID=Mars/Base:20260101T090000", "END:VEVENT"}, `unknown TZID "Mars/Base"`},
+	}
+	for _, tt := range tests {
+		_, err := NewSchedule(time.UTC).LoadICS(writeICS(t, tt.lines...))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: LoadICS error = %v, want it to contain %q", tt.name, err, tt.want)
+		}
+	}
+	if _, err := NewSchedule(time.UTC).LoadICS(filepath.Join(t.TempDir(), "missing.ics")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
@@ -0,0 +1,221 @@
+package guard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schedule is when the bot may run: weekly windows in one timezone, minus holidays.
+// A window whose end is not after its start crosses midnight ("fri 22:00-02:00" runs into Saturday).
+// No window runs on a holiday's date, including the part of an overnight window past midnight.
+type Schedule struct {
+	loc      *time.Location
+	entries  []string          // Window entries as given, for String
+	windows  [7][]window       // Windows by the weekday they start on
+	holidays map[string]string // Date (2006-01-02) -> name, "" if unnamed
+}
+
+// window is one daily range in minutes since midnight; end <= start ends the next day
+type window struct {
+	start, end int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// searchDays is how far ahead Next looks for an open window
+const searchDays = 400
+
+// NewSchedule returns an empty schedule in loc: nothing runs until windows are added.
+func NewSchedule(loc *time.Location) *Schedule {
+	return &Schedule{loc: loc, holidays: make(map[string]string)}
+}
+
+// LoadLocation resolves an IANA timezone name such as "Europe/Berlin". "" and "Local" mean the host's zone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name like \"Europe/Berlin\")", name)
+	}
+	return loc, nil
+}
+
+// Location returns the timezone the schedule's windows and holidays are in.
+func (s *Schedule) Location() *time.Location { return s.loc }
+
+// AddWindow adds a weekly window: days ("mon", "mon-fri", "fri-mon", "daily", "weekdays" or "weekends")
+// and a range, e.g. "mon-fri 09:00-17:30" or "sat 22:00-02:00". "24:00" may end a range.
+func (s *Schedule) AddWindow(entry string) error {
+	fields := strings.Fields(strings.ToLower(entry))
+	if len(fields) != 2 {
+		return fmt.Errorf("window %q must be days and a time range, e.g. \"mon-fri 09:00-17:00\"", entry)
+	}
+	days, err := parseDays(fields[0])
+	if err != nil {
+		return fmt.Errorf("window %q: %w", entry, err)
+	}
+	from, to, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return fmt.Errorf("window %q: time range must be HH:MM-HH:MM", entry)
+	}
+	start, err := parseClock(from)
+	if err != nil || start == 24*60 {
+		return fmt.Errorf("window %q: start %q must be in HH:MM format", entry, from)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return fmt.Errorf("window %q: end %q must be in HH:MM format", entry, to)
+	}
+	for _, day := range days {
+		s.windows[day] = append(s.windows[day], window{start: start, end: end})
+	}
+	s.entries = append(s.entries, strings.Join(fields, " "))
+	return nil
+}
+
+// AddHoliday adds a day off: a date, optionally followed by a name ("2026-12-25 Christmas").
+func (s *Schedule) AddHoliday(entry string) error {
+	date, name, _ := strings.Cut(strings.TrimSpace(entry), " ")
+	day, err := time.ParseInLocation("2006-01-02", date, s.loc)
+	if err != nil {
+		return fmt.Errorf("holiday %q must start with a date in YYYY-MM-DD format", entry)
+	}
+	s.holidays[day.Format("2006-01-02")] = strings.TrimSpace(name)
+	return nil
+}
+
+// Holidays returns how many days off the schedule has.
+func (s *Schedule) Holidays() int { return len(s.holidays) }
+
+// Check returns an error if now is outside every window or on a holiday.
+func (s *Schedule) Check(now time.Time) error {
+	t := now.In(s.loc)
+	if name, ok := s.holiday(t); ok {
+		if name != "" {
+			return fmt.Errorf("bot cannot run on holidays (%s: %s)", t.Format("2006-01-02"), name)
+		}
+		return fmt.Errorf("bot cannot run on holidays (%s)", t.Format("2006-01-02"))
+	}
+	if !s.open(t) {
+		next := "none found"
+		if at, err := s.Next(now); err == nil {
+			next = at.Format("Mon 2006-01-02 15:04")
+		}
+		return fmt.Errorf("bot cannot run outside its schedule (now %s %s, next window %s)", t.Format("Mon 15:04"), s.loc, next)
+	}
+
+	logger.Debug("Within working hours", "schedule", s.String(), "now", t.Format("Mon 15:04"))
+	return nil
+}
+
+// Next returns when the bot may run next, in the schedule's timezone: now if Check passes,
+// otherwise the first instant a window is open on a day that is not a holiday.
+func (s *Schedule) Next(now time.Time) (time.Time, error) {
+	t := now.In(s.loc)
+	if s.allowed(t) {
+		return t, nil
+	}
+	// Windows open either at their start or, after a holiday, at midnight mid-window
+	y, m, d := t.Date()
+	var candidates []time.Time
+	for i := -1; i <= searchDays; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, s.loc)
+		candidates = append(candidates, day)
+		for _, w := range s.windows[day.Weekday()] {
+			candidates = append(candidates, day.Add(time.Duration(w.start)*time.Minute))
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, c := range candidates {
+		if c.After(t) && s.allowed(c) {
+			return c, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("schedule %s has no open window within %d days", s, searchDays)
+}
+
+// String describes the schedule, e.g. "mon-fri 09:00-17:00; sat 10:00-14:00 (Europe/Berlin)".
+func (s *Schedule) String() string {
+	desc := strings.Join(s.entries, "; ")
+	if desc == "" {
+		desc = "never"
+	}
+	return fmt.Sprintf("%s (%s)", desc, s.loc)
+}
+
+// allowed reports whether t (in the schedule's zone) is in a window and not on a holiday
+func (s *Schedule) allowed(t time.Time) bool {
+	_, holiday := s.holiday(t)
+	return !holiday && s.open(t)
+}
+
+// holiday returns the name of the holiday on t's date, if it is one
+func (s *Schedule) holiday(t time.Time) (string, bool) {
+	name, ok := s.holidays[t.Format("2006-01-02")]
+	return name, ok
+}
+
+// open reports whether a window that started today, or overnight yesterday, covers t
+func (s *Schedule) open(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range s.windows[t.Weekday()] {
+		if minute >= w.start && (w.end <= w.start || minute < w.end) {
+			return true
+		}
+	}
+	for _, w := range s.windows[(t.Weekday()+6)%7] {
+		if w.end <= w.start && minute < w.end {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDays expands "mon", "mon-fri", "fri-mon", "daily", "weekdays" or "weekends" into weekdays
+func parseDays(spec string) ([]time.Weekday, error) {
+	switch spec {
+	case "daily":
+		return []time.Weekday{0, 1, 2, 3, 4, 5, 6}, nil
+	case "weekdays":
+		return []time.Weekday{1, 2, 3, 4, 5}, nil
+	case "weekends":
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+	from, to, isRange := strings.Cut(spec, "-")
+	first, ok := weekdays[from]
+	if !ok {
+		return nil, fmt.Errorf("unknown day %q (use mon, tue, ... sun, a range like mon-fri, daily, weekdays or weekends)", from)
+	}
+	if !isRange {
+		return []time.Weekday{first}, nil
+	}
+	last, ok := weekdays[to]
+	if !ok {
+		return nil, fmt.Errorf("unknown day %q (use mon, tue, ... sun, a range like mon-fri, daily, weekdays or weekends)", to)
+	}
+	days := []time.Weekday{first}
+	for d := first; d != last; {
+		d = (d + 1) % 7
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// parseClock returns minutes since midnight for "HH:MM" (00:00 to 24:00)
+func parseClock(hm string) (int, error) {
+	if hm == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", hm)
+	if err != nil || len(hm) != 5 {
+		return 0, fmt.Errorf("invalid time %q", hm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
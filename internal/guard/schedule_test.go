@@ -0,0 +1,142 @@
+package guard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSchedule returns a Berlin schedule with office hours, an overnight Friday window and Christmas off
+func testSchedule(t *testing.T) *Schedule {
+	t.Helper()
+	loc, err := LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	s := NewSchedule(loc)
+	for _, w := range []string{"mon-fri 09:00-17:00", "fri 22:00-02:00"} {
+		if err := s.AddWindow(w); err != nil {
+			t.Fatalf("AddWindow(%q): %v", w, err)
+		}
+	}
+	if err := s.AddHoliday("2026-12-25 Christmas"); err != nil {
+		t.Fatalf("AddHoliday: %v", err)
+	}
+	return s
+}
+
+func TestScheduleCheckAndNext(t *testing.T) {
+	s := testSchedule(t)
+	berlin := s.Location()
+	at := func(date, clock string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, berlin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		open     bool
+		next     time.Time // Only checked when closed
+		errMatch string
+	}{
+		{"office hours", at("2026-03-02", "10:00"), true, time.Time{}, ""},
+		{"UTC input converted", time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC), true, time.Time{}, ""},
+		{"before opening", at("2026-03-02", "08:59"), false, at("2026-03-02", "09:00"), "outside its schedule"},
+		{"end is exclusive", at("2026-03-02", "17:00"), false, at("2026-03-03", "09:00"), "outside its schedule"},
+		{"overnight start", at("2026-03-06", "23:30"), true, time.Time{}, ""},
+		{"overnight tail", at("2026-03-07", "01:30"), true, time.Time{}, ""},
+		{"after overnight", at("2026-03-07", "02:00"), false, at("2026-03-09", "09:00"), "outside its schedule"},
+		{"weekend", at("2026-03-08", "12:00"), false, at("2026-03-09", "09:00"), "outside its schedule"},
+		{"holiday", at("2026-12-25", "10:00"), false, at("2026-12-26", "00:00"), "holidays (2026-12-25: Christmas)"},
+	}
+	for _, tt := range tests {
+		err := s.Check(tt.now)
+		if (err == nil) != tt.open {
+			t.Errorf("%s: Check = %v, want open %v", tt.name, err, tt.open)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.errMatch) {
+			t.Errorf("%s: Check = %v, want it to contain %q", tt.name, err, tt.errMatch)
+		}
+		next, err := s.Next(tt.now)
+		if err != nil {
+			t.Errorf("%s: Next: %v", tt.name, err)
+			continue
+		}
+		want := tt.next
+		if tt.open {
+			want = tt.now
+		}
+		if !next.Equal(want) {
+			t.Errorf("%s: Next = %s, want %s", tt.name, next, want.In(berlin))
+		}
+	}
+}
+
+func TestScheduleNextNever(t *testing.T) {
+	s := NewSchedule(time.UTC)
+	if _, err := s.Next(time.Now()); err == nil {
+		t.Error("Next on an empty schedule: no error")
+	}
+	if got := s.String(); got != "never (UTC)" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestAddWindow(t *testing.T) {
+	tests := []struct {
+		entry   string
+		days    []time.Weekday
+		wantErr bool
+	}{
+		{"mon-fri 09:00-17:00", []time.Weekday{1, 2, 3, 4, 5}, false},
+		{"fri-mon 10:00-12:00", []time.Weekday{5, 6, 0, 1}, false},
+		{"Weekends 10:00-24:00", []time.Weekday{0, 6}, false},
+		{"daily 00:00-24:00", []time.Weekday{0, 1, 2, 3, 4, 5, 6}, false},
+		{"sat 22:00-02:00", []time.Weekday{6}, false},
+		{"mon-fri", nil, true},
+		{"funday 09:00-17:00", nil, true},
+		{"mon 9:00-17:00", nil, true},
+		{"mon 09:00", nil, true},
+		{"mon 24:00-02:00", nil, true},
+	}
+	for _, tt := range tests {
+		s := NewSchedule(time.UTC)
+		err := s.AddWindow(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("AddWindow(%q) error = %v, want error %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		var days []time.Weekday
+		for d, windows := range s.windows {
+			if len(windows) > 0 {
+				days = append(days, time.Weekday(d))
+			}
+		}
+		want := slices.Clone(tt.days)
+		slices.Sort(want)
+		if !slices.Equal(days, want) {
+			t.Errorf("AddWindow(%q) days = %v, want %v", tt.entry, days, want)
+		}
+	}
+}
+
+func TestAddHoliday(t *testing.T) {
+	s := NewSchedule(time.UTC)
+	for _, entry := range []string{"2026-12-25 Christmas Day", "2026-12-26", "2026-12-25"} {
+		if err := s.AddHoliday(entry); err != nil {
+			t.Errorf("AddHoliday(%q): %v", entry, err)
+		}
+	}
+	if s.Holidays() != 2 {
+		t.Errorf("Holidays = %d, want 2", s.Holidays())
+	}
+	if err := s.AddHoliday("25.12.2026"); err == nil {
+		t.Error("AddHoliday with a bad date: no error")
+	}
+}
@@ -1,32 +1,35 @@
 package linkedin
 
 import (
-	"encoding/json"
+	"database/sql"
 	"errors"
-	"log"
-	"os"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/session"
 	"github.com/SNKT2024/linkedin-automation/internal/stealth"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 )
 
-const cookiesFile = "cookies.json"
-
-// Login handles LinkedIn authentication with "Fail Fast" logic
-func Login(browser *rod.Browser, page *rod.Page, cfg *config.Config) error {
+// Login handles LinkedIn authentication with "Fail Fast" logic, reusing the session saved in db when it is still valid
+func Login(browser *rod.Browser, page *rod.Page, db *sql.DB, cfg *config.Config) error {
 	email := cfg.Email
 	password := cfg.Password
+	ep, sel := cfg.Endpoints, cfg.Selectors
 
 	// 1. Try Cookie Login
-	if err := loadCookies(browser); err == nil {
-		log.Println("🍪 Cookies loaded. Checking validity...")
+	if err := loadCookies(browser, db, cfg); err != nil {
+		logger.Info("🍪 No usable saved session", "reason", err.Error())
+	} else {
+		logger.Info("🍪 Cookies loaded. Checking validity")
+
+		page.MustNavigate(ep.FeedURL())
 
-		page.MustNavigate("https://www.linkedin.com/feed/")
-		
 		// Wait a moment for redirect to happen
 		// (LinkedIn takes 1-2 seconds to decide if cookies are good or bad)
 		time.Sleep(3 * time.Second)
@@ -34,80 +37,92 @@ func Login(browser *rod.Browser, page *rod.Page, cfg *config.Config) error {
 		// 2. FAIL FAST CHECK
 		// Instead of waiting 15s, we check URL immediately.
 		currentURL := page.MustInfo().URL
-		
-		if strings.Contains(currentURL, "/feed") || strings.Contains(currentURL, "/mini-profile") {
-			log.Println("✅ Cookies are valid! (Feed detected)")
+
+		if ep.IsFeedURL(currentURL) || strings.Contains(currentURL, "/mini-profile") {
+			logger.Info("✅ Cookies are valid (Feed detected)")
 			return nil
 		}
 
 		// If we are redirected to /login or /uas/login, cookies are dead.
-		if strings.Contains(currentURL, "/login") || strings.Contains(currentURL, "uas/authenticate") {
-			log.Println("🚫 Cookies expired (Redirected to Login). Switching to manual login immediately...")
+		if ep.IsLoginURL(currentURL) || strings.Contains(currentURL, "uas/authenticate") {
+			logger.Warn("🚫 Cookies expired (Redirected to Login). Switching to manual login immediately", "url", currentURL)
 			// Fall through to Manual Login below
 		} else {
 			// Edge case: Maybe internet is slow? Give it one last verification check.
-			if verifyLogin(page) {
+			if verifyLogin(page, ep, sel) {
 				return nil
 			}
-			log.Println("⚠️ Cookie login inconclusive. Switching to manual.")
+			logger.Warn("⚠️ Cookie login inconclusive. Switching to manual", "url", currentURL)
 		}
 	}
 
 	// 3. Manual Login (The Fallback)
-	log.Println("🔓 Starting Manual Login...")
-	
+	logger.Info("🔓 Starting Manual Login")
+
 	// Critical: Clear invalid cookies first so LinkedIn doesn't loop
 	browser.MustSetCookies() // Clears all cookies
-	
-	page.MustNavigate("https://www.linkedin.com/login")
+
+	page.MustNavigate(ep.LoginURL())
 	page.MustWaitLoad()
 	stealth.RandomSleep(2000, 3000)
 
 	// Fill Email
-	log.Println("   ✍️ Filling Email...")
-	emailInput, err := page.Element("#username")
-	if err != nil { return err }
+	logger.Debug("✍️ Filling Email")
+	emailInput, err := find(page, sel, selectors.LoginUsername, 10*time.Second)
+	if err != nil {
+		return err
+	}
 	stealth.HumanType(emailInput, email)
 	stealth.RandomSleep(1000, 2000)
 
 	// Fill Password
-	log.Println("   ✍️ Filling Password...")
-	passInput, err := page.Element("#password")
-	if err != nil { return err }
+	logger.Debug("✍️ Filling Password")
+	passInput, err := find(page, sel, selectors.LoginPassword, 10*time.Second)
+	if err != nil {
+		return err
+	}
 	stealth.HumanType(passInput, password)
 	stealth.RandomSleep(1000, 2000)
 
 	// Click Sign In
-	log.Println("   🚀 Clicking Sign In...")
-	// Try multiple selectors for the button
-	btn, err := page.Element("button[type='submit'], .login__form_action_container button")
-	if err != nil { return errors.New("could not find login button") }
-	
+	logger.Debug("🚀 Clicking Sign In")
+	// The registry lists several fallbacks for the button
+	btn, err := find(page, sel, selectors.LoginSubmit, 5*time.Second)
+	if err != nil {
+		return errors.New("could not find login button")
+	}
+
 	stealth.HumanClick(page, btn)
 	page.MustWaitLoad()
-	
+
 	// Wait for feed to confirm success
-	log.Println("   ⏳ Waiting for Feed...")
-	
+	logger.Info("⏳ Waiting for Feed")
+
 	// Robust verification loop (Wait up to 30s for manual login to process)
-	if verifyLogin(page) {
-		log.Println("✅ Manual Login Successful!")
-		saveCookies(browser) // Save fresh cookies for next time
+	if verifyLogin(page, ep, sel) {
+		logger.Info("✅ Manual Login Successful")
+		if err := saveCookies(browser, db, cfg); err != nil { // Save fresh cookies for next time
+			logger.Warn("⚠️ Failed to save cookies", logging.Err(err))
+		}
 		return nil
 	}
 
+	// A checkpoint or restriction page instead of the feed trips the circuit breaker
+	if err := checkWarnings(page, sel); err != nil {
+		return err
+	}
 	return errors.New("manual login failed (timeout waiting for feed)")
 }
 
 // verifyLogin waits up to 15 seconds for signs of a successful login
-func verifyLogin(page *rod.Page) bool {
+func verifyLogin(page *rod.Page, ep config.Endpoints, sel *selectors.Registry) bool {
 	// Poll every 1 second for 15 seconds
 	for i := 0; i < 15; i++ {
-		if strings.Contains(page.MustInfo().URL, "/feed") {
+		if ep.IsFeedURL(page.MustInfo().URL) {
 			return true
 		}
 		// Check for global nav bar (strong indicator of logged-in state)
-		if _, err := page.Element("#global-nav"); err == nil {
+		if has(page, sel, selectors.GlobalNav, 0) {
 			return true
 		}
 		time.Sleep(1 * time.Second)
@@ -115,14 +130,16 @@ func verifyLogin(page *rod.Page) bool {
 	return false
 }
 
-// loadCookies loads cookies from file
-func loadCookies(browser *rod.Browser) error {
-	file, err := os.Open(cookiesFile)
-	if err != nil { return err }
-	defer file.Close()
-
-	var cookies []*proto.NetworkCookie
-	if err := json.NewDecoder(file).Decode(&cookies); err != nil { return err }
+// loadCookies sets the account's saved session cookies on the browser.
+// A session whose auth cookie has already expired is not loaded.
+func loadCookies(browser *rod.Browser, db *sql.DB, cfg *config.Config) error {
+	cookies, s, err := session.Load(db, cfg.Secrets, cfg.Email)
+	if err != nil {
+		return err
+	}
+	if remaining, ok := session.Remaining(s, time.Now()); ok && remaining <= 0 {
+		return fmt.Errorf("saved session expired on %s", s.ExpiresAt.Time.Local().Format("2006-01-02 15:04"))
+	}
 
 	// Convert NetworkCookie to NetworkCookieParam
 	cookieParams := make([]*proto.NetworkCookieParam, len(cookies))
@@ -142,13 +159,19 @@ func loadCookies(browser *rod.Browser) error {
 	return browser.SetCookies(cookieParams)
 }
 
-// saveCookies saves active cookies to file
-func saveCookies(browser *rod.Browser) error {
+// saveCookies saves the browser's cookies as the account's session
+func saveCookies(browser *rod.Browser, db *sql.DB, cfg *config.Config) error {
 	cookies, err := browser.GetCookies()
-	if err != nil { return err }
-
-	data, err := json.MarshalIndent(cookies, "", "  ")
-	if err != nil { return err }
+	if err != nil {
+		return err
+	}
 
-	return os.WriteFile(cookiesFile, data, 0644)
-}
\ No newline at end of file
+	s, err := session.Save(db, cfg.Secrets, cfg.Email, cookies)
+	if err != nil {
+		return err
+	}
+	if remaining, ok := session.Remaining(s, time.Now()); ok {
+		logger.Info("🍪 Session saved", "cookies", s.CookieCount, "valid_for", session.FormatDuration(remaining), "expires_at", s.ExpiresAt.Time)
+	}
+	return nil
+}
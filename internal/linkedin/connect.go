@@ -1,55 +1,94 @@
 package linkedin
 
 import (
+	"database/sql"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"math/rand"
 	"time"
 
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
 	"github.com/SNKT2024/linkedin-automation/internal/stealth"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/SNKT2024/linkedin-automation/internal/template"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 )
 
 // ConnectWithProfile attempts to send a connection request with an optional note.
-func ConnectWithProfile(page *rod.Page, profileURL string, message string) (string, error) {
-	log.Printf("Navigating to profile: %s", profileURL)
+// The profile's stored details are refreshed from the page before the note template is rendered.
+// profile.URL must be a profile on the host configured in cfg.Endpoints.
+// Profiles on the do-not-contact list are checked before the visit and again with the
+// refreshed details, and return "skipped_suppressed" without any action.
+// With cfg.DryRun the page is still inspected, but instead of clicking "Connect" the note is
+// rendered and recorded, and "dry_run" is returned.
+// The visit and the invite are each reserved from budget first, and the page is checked for
+// account warnings after the visit and after clicking "Connect". If the budget refuses or the
+// circuit breaker trips, "stopped" is returned with the error.
+func ConnectWithProfile(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget, profile storage.Profile, note *template.Template) (string, error) {
+	ep, sel := cfg.Endpoints, cfg.Selectors
+	profileURL := profile.URL
+	if !ep.IsProfileURL(profileURL) {
+		return "failed", fmt.Errorf("not a profile URL on %s: %s", ep.BaseURL, profileURL)
+	}
+	if s, skip := checkSuppressed(db, profile); skip {
+		if s == nil {
+			return "failed", errors.New("suppression check failed")
+		}
+		return "skipped_suppressed", nil
+	}
 
-	page.MustNavigate(profileURL)
-	page.MustWaitLoad()
+	plog := logger.With(logging.Profile(profileURL))
+	plog.Info("Navigating to profile")
 
-	log.Println("Reading profile...")
+	if err := visitProfile(page, db, sel, budget, profileURL, "connect"); err != nil {
+		return "stopped", err
+	}
+
+	plog.Debug("Reading profile")
 	stealth.RandomSleep(3000, 5000)
+	profile = refreshProfile(page, db, sel, profile)
+	if s, skip := checkSuppressed(db, profile); skip {
+		if s == nil {
+			return "failed", errors.New("suppression check failed")
+		}
+		return "skipped_suppressed", nil
+	}
 	stealth.NaturalScroll(page, 300+rand.Intn(200))
-	
-	// 1. CRITICAL: Only check for "Pending" first. 
+
+	// 1. CRITICAL: Only check for "Pending" first.
 	// DO NOT check for "Message" here, or we will skip Open Profiles.
-	if exists(page, "button", "Pending") { return "skipped_pending", nil }
-	if exists(page, "button", "Withdraw") { return "skipped_pending", nil }
-	
+	if has(page, sel, selectors.ProfilePending, 1*time.Second) {
+		return "skipped_pending", nil
+	}
+
 	// 2. HUNT FOR CONNECT BUTTON (Priority A: Direct)
-	log.Println("Looking for 'Connect' button...")
+	plog.Debug("Looking for 'Connect' button")
 	var connectBtn *rod.Element
-	
+
 	// Try Direct Button
-	if btn, err := page.Timeout(3 * time.Second).ElementR("button", "^Connect$"); err == nil {
+	if btn, err := find(page, sel, selectors.ConnectButton, 3*time.Second); err == nil {
 		connectBtn = btn
-		log.Println("✅ Found direct 'Connect' button")
+		plog.Debug("✅ Found direct 'Connect' button")
 	} else {
 		// Try "More" Dropdown (Priority B)
-		log.Println("Direct button missing. Checking 'More' dropdown...")
+		plog.Debug("Direct button missing. Checking 'More' dropdown")
 		// Click "More" to open the menu
-		if moreBtn, err := page.Timeout(3 * time.Second).ElementR("button", "^More$|More actions"); err == nil {
+		if moreBtn, err := find(page, sel, selectors.ConnectMore, 3*time.Second); err == nil {
 			stealth.HumanClick(page, moreBtn)
 			stealth.RandomSleep(1000, 2000)
-			
+
 			// Look for Connect inside the menu
-			if dropBtn, err := page.Timeout(3 * time.Second).ElementR("div[role='menuitem'], button, span", "^Connect$"); err == nil {
+			if dropBtn, err := find(page, sel, selectors.ConnectMenuItem, 3*time.Second); err == nil {
 				connectBtn = dropBtn
-				log.Println("✅ Found 'Connect' in dropdown")
+				plog.Debug("✅ Found 'Connect' in dropdown")
 			} else {
 				// Close dropdown if Connect wasn't found (click body)
-				page.Mouse.Click(proto.InputMouseButtonLeft, 1) 
+				page.Mouse.Click(proto.InputMouseButtonLeft, 1)
 			}
 		}
 	}
@@ -60,69 +99,102 @@ func ConnectWithProfile(page *rod.Page, profileURL string, message string) (stri
 		connectBtn.MustScrollIntoView()
 		stealth.RandomSleep(500, 1000)
 
-		log.Println("🚀 Clicking 'Connect'...")
+		text := noteText(personalize(note, profile))
+		if cfg.DryRun {
+			plog.Info("🧪 [DRY RUN] Would click 'Connect'")
+			handleConnectionDialog(page, plog, sel, text, true)
+			recordDryRun(db, profileURL, "connect", "would_invite", text)
+			return "dry_run", nil
+		}
+
+		if err := budget.Reserve(storage.ActionInvite); err != nil {
+			return "stopped", err
+		}
+		plog.Info("🚀 Clicking 'Connect'")
 		stealth.HumanClick(page, connectBtn)
 		stealth.RandomSleep(2000, 3000)
 
-		// Handle the Note/Send Dialog
-		handleConnectionDialog(page, message)
+		// Handle the Note/Send Dialog (personalized with the refreshed name)
+		// Clicking Connect may already send the invite, so it counts against the limits either way
+		typed, sent := handleConnectionDialog(page, plog, sel, text, false)
+		if sent {
+			recordOutbound(db, profileURL, storage.OutboundNote, note, typed)
+		}
+		recordAction(db, storage.ActionInvite, profileURL, 1, note.Name())
+
+		// The weekly invitation limit shows up only once "Connect" is clicked
+		if err := checkWarnings(page, sel); err != nil {
+			return "stopped", err
+		}
 		return "clicked", nil
 	}
 
 	// 4. IF CONNECT NOT FOUND -> CHECK IF ALREADY CONNECTED
 	// Now it is safe to check for "Message", because we confirmed "Connect" is missing.
-	if exists(page, "button", "^Message$") {
-		log.Println("⚠️ No 'Connect' button, but 'Message' exists -> Already Connected.")
+	if has(page, sel, selectors.ProfileMessage, 1*time.Second) {
+		plog.Info("⚠️ No 'Connect' button, but 'Message' exists -> Already Connected")
 		return "skipped_connected", nil
 	}
 
 	// 5. CHECK FOR LOCKED/PREMIUM
-	errInMail := rod.Try(func() {
-		page.Timeout(2 * time.Second).MustElement(`button[aria-label*="Send InMail"], .premium-inmail-button`)
-	})
-	if errInMail == nil { return "skipped_premium", nil }
+	if has(page, sel, selectors.ProfileInMail, 2*time.Second) {
+		return "skipped_premium", nil
+	}
 
-	log.Println("❌ Could not find Connect button (and not connected).")
+	plog.Warn("❌ Could not find Connect button (and not connected)")
 	return "failed", errors.New("connect button not found")
 }
 
-// handleConnectionDialog adds a note if message is provided
-func handleConnectionDialog(page *rod.Page, message string) {
-	log.Println("Handling connection dialog...")
+// noteText truncates a connection note to the site's note limit
+func noteText(message string) string {
+	if runes := []rune(message); len(runes) > template.ConnectNoteLimit {
+		return string(runes[:template.ConnectNoteLimit])
+	}
+	return message
+}
+
+// handleConnectionDialog adds a note if message is provided.
+// It returns the note actually typed ("" if none) and whether "Send" was clicked.
+// With dryRun it only logs what it would type and click; the dialog is not open then.
+func handleConnectionDialog(page *rod.Page, plog *slog.Logger, sel *selectors.Registry, message string, dryRun bool) (string, bool) {
+	if dryRun {
+		if message != "" {
+			plog.Info("🧪 [DRY RUN] Would click 'Add a note' and type", "chars", len([]rune(message)), "note", message)
+		}
+		plog.Info("🧪 [DRY RUN] Would click 'Send'")
+		return "", false
+	}
+
+	typed := ""
+
+	plog.Debug("Handling connection dialog")
 
 	// IF message exists, try to click "Add a note"
 	if message != "" {
-		if noteBtn, err := page.Timeout(3 * time.Second).ElementR("button", "Add a note"); err == nil {
-			log.Println("📝 Clicking 'Add a note'...")
+		if noteBtn, err := find(page, sel, selectors.ConnectAddNote, 3*time.Second); err == nil {
+			plog.Debug("📝 Clicking 'Add a note'")
 			stealth.HumanClick(page, noteBtn)
 			stealth.RandomSleep(1000, 2000)
 
 			// Type Message
-			if textArea, err := page.Element("textarea"); err == nil {
-				// Truncate to 300 chars (LinkedIn Limit)
-				if len(message) > 300 { message = message[:300] }
-				
-				log.Printf("✍️ Typing note: '%s...'", message[:15])
+			if textArea, err := find(page, sel, selectors.ConnectNoteInput, 3*time.Second); err == nil {
+				plog.Info("✍️ Typing note", "preview", preview(message, 15))
 				stealth.HumanType(textArea, message)
 				stealth.RandomSleep(1000, 2000)
+				typed = message
 			}
 		} else {
-			log.Println("⚠️ 'Add a note' button not found. Sending without note.")
+			plog.Warn("⚠️ 'Add a note' button not found. Sending without note")
 		}
 	}
 
 	// Click "Send" (Works for both "Send now" and "Send" after writing note)
-	if sendBtn, err := page.Timeout(3 * time.Second).ElementR("button", "Send|Send now|Send without a note"); err == nil {
-		log.Println("🚀 Clicking Send...")
+	if sendBtn, err := find(page, sel, selectors.ConnectSend, 3*time.Second); err == nil {
+		plog.Info("🚀 Clicking Send")
 		stealth.HumanClick(page, sendBtn)
 		stealth.RandomSleep(2000, 3000)
-	} else {
-		log.Println("⚠️ 'Send' button not found (Email verification might be required)")
+		return typed, true
 	}
+	plog.Warn("⚠️ 'Send' button not found (Email verification might be required)")
+	return typed, false
 }
-
-// Helper to quickly check for element existence by text
-func exists(page *rod.Page, selector, textRegex string) bool {
-	_, err := page.Timeout(1 * time.Second).ElementR(selector, textRegex)
-	return err == nil
-}
\ No newline at end of file
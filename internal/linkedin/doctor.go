@@ -0,0 +1,114 @@
+package linkedin
+
+import (
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/go-rod/rod"
+)
+
+// Match statuses reported by the selector doctor.
+const (
+	MatchNone = "none"
+	MatchOne  = "one"
+	MatchMany = "many"
+)
+
+// DoctorReport is the machine-readable result of a selector health check.
+// It has no timestamps so reports from different runs can be diffed directly.
+type DoctorReport struct {
+	Selectors string             `json:"selectors"`
+	Version   int                `json:"version"`
+	Pages     []PageCheck        `json:"pages"`
+	Summary   map[string]KeyStat `json:"summary"`
+}
+
+// PageCheck lists the selector matches found on a single page or snapshot.
+type PageCheck struct {
+	Source  string     `json:"source"`
+	Results []KeyCheck `json:"results"`
+}
+
+// KeyCheck is the match result of one selector key on one page.
+// Count is taken from the first candidate that matched, mirroring how the bot resolves keys.
+type KeyCheck struct {
+	Key             string `json:"key"`
+	Status          string `json:"status"`
+	Count           int    `json:"count"`
+	Candidate       int    `json:"candidate"` // Index of the candidate used, -1 if none matched
+	CandidateCounts []int  `json:"candidate_counts"`
+}
+
+// KeyStat summarizes one key across every checked page.
+type KeyStat struct {
+	Status     string   `json:"status"`
+	MatchedOn  []string `json:"matched_on"`
+	MaxMatches int      `json:"max_matches"`
+}
+
+// NewDoctorReport returns an empty report for the given registry.
+func NewDoctorReport(reg *selectors.Registry) *DoctorReport {
+	return &DoctorReport{
+		Selectors: reg.Name(),
+		Version:   reg.Version(),
+		Summary:   make(map[string]KeyStat),
+	}
+}
+
+// CheckSelectors counts how many elements every registered key matches on the current page
+// and adds the result to the report.
+func CheckSelectors(page *rod.Page, reg *selectors.Registry, source string, report *DoctorReport) {
+	logger.Info("🩺 Checking selectors", "count", len(selectors.Keys()), "source", source)
+	check := PageCheck{Source: source}
+
+	for _, key := range selectors.Keys() {
+		result := KeyCheck{Key: string(key), Candidate: -1}
+		for i, c := range reg.Get(key) {
+			count := countCandidate(page, c)
+			result.CandidateCounts = append(result.CandidateCounts, count)
+			if result.Candidate == -1 && count > 0 {
+				result.Candidate = i
+				result.Count = count
+			}
+		}
+		result.Status = matchStatus(result.Count)
+		check.Results = append(check.Results, result)
+
+		stat := report.Summary[string(key)]
+		if result.Count > 0 {
+			stat.MatchedOn = append(stat.MatchedOn, source)
+		}
+		if result.Count > stat.MaxMatches {
+			stat.MaxMatches = result.Count
+		}
+		stat.Status = matchStatus(stat.MaxMatches)
+		if stat.MatchedOn == nil {
+			stat.MatchedOn = []string{}
+		}
+		report.Summary[string(key)] = stat
+	}
+
+	report.Pages = append(report.Pages, check)
+}
+
+// countCandidate returns how many elements match a single candidate (without waiting)
+func countCandidate(page *rod.Page, c selectors.Candidate) int {
+	elements, err := page.Elements(c.CSS)
+	if err != nil {
+		return 0
+	}
+	if c.Text != "" {
+		elements = filterByText(elements, c.Text)
+	}
+	return len(elements)
+}
+
+// matchStatus maps an element count to none/one/many
+func matchStatus(count int) string {
+	switch {
+	case count == 0:
+		return MatchNone
+	case count == 1:
+		return MatchOne
+	default:
+		return MatchMany
+	}
+}
@@ -0,0 +1,101 @@
+package linkedin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+)
+
+// withSelector returns a copy of reg in which key has only the given candidates
+func withSelector(t *testing.T, reg *selectors.Registry, key selectors.Key, candidates ...selectors.Candidate) *selectors.Registry {
+	t.Helper()
+	entries := make(map[selectors.Key][]selectors.Candidate)
+	for _, k := range selectors.Keys() {
+		entries[k] = reg.Get(k)
+	}
+	entries[key] = candidates
+	data, err := json.Marshal(map[string]any{"name": reg.Name() + "-broken", "version": reg.Version(), "selectors": entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken, err := selectors.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return broken
+}
+
+// keyCheck returns the result for key on a checked page
+func keyCheck(t *testing.T, check PageCheck, key selectors.Key) KeyCheck {
+	t.Helper()
+	for _, result := range check.Results {
+		if result.Key == string(key) {
+			return result
+		}
+	}
+	t.Fatalf("%s: no result for %s", check.Source, key)
+	return KeyCheck{}
+}
+
+func TestDoctorFlagsBrokenSelectorE2E(t *testing.T) {
+	r := startE2E(t)
+	profileURL := r.baseURL + "/in/test-user-01/" // Direct "Connect"
+	r.page.MustNavigate(profileURL)
+	r.page.MustWaitLoad()
+
+	healthy := r.cfg.Selectors
+	broken := withSelector(t, healthy, selectors.ConnectButton, selectors.Candidate{CSS: "button.connect-button-from-last-year"})
+	report := NewDoctorReport(broken)
+	CheckSelectors(r.page, healthy, "healthy", report)
+	CheckSelectors(r.page, broken, "broken", report)
+	if len(report.Pages) != 2 {
+		t.Fatalf("report has %d pages, want 2", len(report.Pages))
+	}
+
+	tests := []struct {
+		page       PageCheck
+		key        selectors.Key
+		want       string
+		wantCounts int // Length of CandidateCounts: one per candidate tried
+	}{
+		{report.Pages[0], selectors.ConnectButton, MatchOne, len(healthy.Get(selectors.ConnectButton))},
+		{report.Pages[1], selectors.ConnectButton, MatchNone, 1},
+		{report.Pages[1], selectors.ProfileName, MatchOne, len(healthy.Get(selectors.ProfileName))},
+	}
+	for _, tt := range tests {
+		got := keyCheck(t, tt.page, tt.key)
+		if got.Status != tt.want || len(got.CandidateCounts) != tt.wantCounts {
+			t.Errorf("%s on %s: %s with %d candidate counts, want %s with %d", tt.key, tt.page.Source, got.Status, len(got.CandidateCounts), tt.want, tt.wantCounts)
+		}
+		if tt.want == MatchNone && got.Candidate != -1 {
+			t.Errorf("%s on %s: candidate %d, want -1", tt.key, tt.page.Source, got.Candidate)
+		}
+	}
+
+	// The summary keeps the healthy page's match, so the broken one must be read per page
+	if stat := report.Summary[string(selectors.ConnectButton)]; len(stat.MatchedOn) != 1 || stat.MatchedOn[0] != "healthy" {
+		t.Errorf("connect.button matched on %q, want only the healthy run", stat.MatchedOn)
+	}
+}
+
+func TestDoctorReportsUnmatchedKeysE2E(t *testing.T) {
+	r := startE2E(t)
+	r.page.MustNavigate(r.baseURL + "/in/test-user-01/")
+	r.page.MustWaitLoad()
+
+	broken := withSelector(t, r.cfg.Selectors, selectors.ProfileName, selectors.Candidate{CSS: "h1.renamed-heading"}, selectors.Candidate{CSS: "h2", Text: "^No such name$"})
+	report := NewDoctorReport(broken)
+	CheckSelectors(r.page, broken, "profile", report)
+
+	stat := report.Summary[string(selectors.ProfileName)]
+	if stat.Status != MatchNone || len(stat.MatchedOn) != 0 || stat.MaxMatches != 0 {
+		t.Errorf("profile.name summary = %+v, want no match anywhere", stat)
+	}
+	if got := keyCheck(t, report.Pages[0], selectors.ProfileName); len(got.CandidateCounts) != 2 || got.CandidateCounts[0] != 0 || got.CandidateCounts[1] != 0 {
+		t.Errorf("profile.name candidate counts = %v, want [0 0]", got.CandidateCounts)
+	}
+	if report.Selectors != r.cfg.Selectors.Name()+"-broken" {
+		t.Errorf("report names selectors %q, want the broken set", report.Selectors)
+	}
+}
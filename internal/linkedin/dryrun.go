@@ -0,0 +1,38 @@
+package linkedin
+
+import (
+	"database/sql"
+
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+)
+
+// recordDryRun logs and stores what an action would have done in dry-run mode
+func recordDryRun(db *sql.DB, profileURL, action, outcome, text string) {
+	plog := logger.With(logging.Profile(profileURL))
+	plog.Info("🧪 [DRY RUN] Recorded outcome", "action", action, "outcome", outcome)
+	if err := storage.RecordDryRun(db, profileURL, action, outcome, text); err != nil {
+		plog.Warn("⚠️ Failed to record dry-run result", logging.Err(err))
+	}
+}
+
+// setStatus updates the profile status, or in dry-run mode only records the status it would have set
+func setStatus(db *sql.DB, cfg *config.Config, action, profileURL string, status storage.ProfileStatus, reason string) {
+	if cfg.DryRun {
+		recordDryRun(db, profileURL, action, string(status), reason)
+		return
+	}
+	if err := storage.UpdateStatus(db, profileURL, status, reason); err != nil {
+		logger.Error("❌ Failed to update profile status", logging.Profile(profileURL), "status", status, logging.Err(err))
+	}
+}
+
+// preview shortens text for log lines
+func preview(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
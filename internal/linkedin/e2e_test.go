@@ -0,0 +1,188 @@
+package linkedin
+
+import (
+	"database/sql"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SNKT2024/linkedin-automation/internal/browser"
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/fakesite"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+// e2eRun is the bot logged into a fresh fake site, with its own database
+type e2eRun struct {
+	site    *fakesite.Site
+	baseURL string
+	cfg     *config.Config
+	db      *sql.DB
+	budget  *guard.Budget
+	page    *rod.Page
+}
+
+// unavailable skips the test because the end-to-end run cannot happen here, or fails it when
+// E2E=1 demands the run
+func unavailable(t *testing.T, format string, args ...any) {
+	t.Helper()
+	if os.Getenv("E2E") == "1" {
+		t.Fatalf("E2E=1: "+format, args...)
+	}
+	t.Skipf(format+" (set E2E=1 to require this test)", args...)
+}
+
+// startE2E serves a fake site, loads the configuration pointing at it and logs in with a headless
+// browser. Without E2E=1 the test is skipped in short mode (the bot keeps its human pace) and
+// when no local Chrome or Chromium is installed.
+func startE2E(t *testing.T) *e2eRun {
+	t.Helper()
+	if testing.Short() && os.Getenv("E2E") != "1" {
+		t.Skip("end-to-end test runs at human pace")
+	}
+	bin, ok := launcher.LookPath()
+	if !ok {
+		unavailable(t, "no Chrome or Chromium installed")
+	}
+
+	site := fakesite.New("test@example.com", "password")
+	srv := fakesite.NewServer(site)
+	t.Cleanup(srv.Close)
+
+	t.Chdir(t.TempDir())
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("LINKEDIN_EMAIL", site.Email)
+	t.Setenv("LINKEDIN_PASSWORD", site.Password)
+	t.Setenv("LINKEDIN_BASE_URL", srv.URL)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	storage.ConfigureCanonical(cfg.Endpoints.BaseURL, cfg.Endpoints.ProfilePath)
+	t.Cleanup(func() {
+		defaults := config.DefaultEndpoints()
+		storage.ConfigureCanonical(defaults.BaseURL, defaults.ProfilePath)
+	})
+
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := storage.UseCampaign(db, cfg.Campaign); err != nil {
+		t.Fatalf("UseCampaign: %v", err)
+	}
+
+	l := launcher.New().Bin(bin).Headless(true).Leakless(false)
+	controlURL, err := l.Launch()
+	if err != nil {
+		unavailable(t, "browser did not start: %v", err)
+	}
+	t.Cleanup(l.Kill)
+	b := rod.New().ControlURL(controlURL)
+	if err := b.Connect(); err != nil {
+		unavailable(t, "browser did not connect: %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	page, err := browser.NewStealthPage(b)
+	if err != nil {
+		t.Fatalf("NewStealthPage: %v", err)
+	}
+
+	if err := Login(b, page, db, cfg); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return &e2eRun{site: site, baseURL: srv.URL, cfg: cfg, db: db, budget: guard.NewBudget(db, cfg.Budgets(), nil), page: page}
+}
+
+// addProfile stores a fake site profile as found by search, then moves it to status.
+// Its name and headline are left for the bot to read from the profile page.
+func (r *e2eRun) addProfile(t *testing.T, slug string, status storage.ProfileStatus) storage.Profile {
+	t.Helper()
+	p := storage.Profile{URL: r.baseURL + "/in/" + slug + "/"}
+	if _, err := storage.AddProfile(r.db, p); err != nil {
+		t.Fatalf("AddProfile(%s): %v", slug, err)
+	}
+	if status != storage.StatusFound {
+		if err := storage.UpdateStatus(r.db, p.URL, status, "e2e setup"); err != nil {
+			t.Fatalf("UpdateStatus(%s): %v", slug, err)
+		}
+	}
+	stored, err := storage.GetProfile(r.db, p.URL)
+	if err != nil {
+		t.Fatalf("GetProfile(%s): %v", slug, err)
+	}
+	return *stored
+}
+
+func TestConnectE2E(t *testing.T) {
+	r := startE2E(t)
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"test-user-01", "clicked"},           // Direct "Connect"
+		{"test-user-02", "clicked"},           // "Connect" behind "More"
+		{"test-user-03", "skipped_pending"},   // Invite already sent
+		{"test-user-04", "skipped_connected"}, // Already a connection
+		{"test-user-06", "skipped_premium"},   // Only InMail
+	}
+	for _, tt := range tests {
+		p := r.addProfile(t, tt.slug, storage.StatusFound)
+		got, err := ConnectWithProfile(r.page, r.db, r.cfg, r.budget, p, r.cfg.ConnectTemplate)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ConnectWithProfile = %q, %v; want %q", tt.slug, got, err, tt.want)
+		}
+	}
+
+	var slugs []string
+	for _, invite := range r.site.Invites() {
+		slugs = append(slugs, invite.Slug)
+		// The note is personalized with the name read from the profile page
+		if want := "Hi Test" + strings.TrimPrefix(invite.Slug, "test-user-"); !strings.HasPrefix(invite.Note, want) {
+			t.Errorf("%s: note %q, want it to start with %q", invite.Slug, invite.Note, want)
+		}
+	}
+	if want := []string{"test-user-01", "test-user-02"}; !slices.Equal(slugs, want) {
+		t.Errorf("invites sent to %q, want %q", slugs, want)
+	}
+	if n, err := r.budget.CountToday(storage.ActionInvite); err != nil || n != 2 {
+		t.Errorf("invites in the ledger = %d, %v; want 2", n, err)
+	}
+}
+
+func TestSendMessagesE2E(t *testing.T) {
+	r := startE2E(t)
+	r.site.AddProfile(fakesite.Profile{Slug: "jane-e2e", Name: "Jane Doe", Headline: "Engineer", State: fakesite.StateConnected})
+	jane := r.addProfile(t, "jane-e2e", storage.StatusInvited)
+	optedOut := r.addProfile(t, "test-user-10", storage.StatusInvited) // Replied "Please stop messaging me."
+
+	if err := SendMessages(r.page, r.db, r.cfg, r.budget); err != nil {
+		t.Fatalf("SendMessages: %v", err)
+	}
+
+	messages := r.site.Messages()
+	if len(messages) != 1 || messages[0].Slug != "jane-e2e" || !strings.HasPrefix(messages[0].Text, "Hi Jane, thanks for connecting!") {
+		t.Errorf("messages = %+v, want one follow-up to jane-e2e", messages)
+	}
+	tests := []struct {
+		url  string
+		want storage.ProfileStatus
+	}{
+		{jane.URL, storage.StatusMessaged},
+		{optedOut.URL, storage.StatusOptedOut},
+	}
+	for _, tt := range tests {
+		p, err := storage.GetProfile(r.db, tt.url)
+		if err != nil {
+			t.Errorf("GetProfile(%s): %v", tt.url, err)
+		} else if p.Status != tt.want {
+			t.Errorf("%s: status %s, want %s", tt.url, p.Status, tt.want)
+		}
+	}
+}
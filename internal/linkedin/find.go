@@ -0,0 +1,98 @@
+package linkedin
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/go-rod/rod"
+)
+
+// find polls every candidate registered for key until one matches or timeout expires.
+// The element is resolved on the original page (no timeout context), so it is safe to
+// click or type into afterwards.
+func find(page *rod.Page, reg *selectors.Registry, key selectors.Key, timeout time.Duration) (*rod.Element, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		for _, c := range reg.Get(key) {
+			if el := matchCandidate(page, c); el != nil {
+				return el, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("selector %s not found", key)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// has reports whether key matches within timeout
+func has(page *rod.Page, reg *selectors.Registry, key selectors.Key, timeout time.Duration) bool {
+	_, err := find(page, reg, key, timeout)
+	return err == nil
+}
+
+// findIn looks for key inside parent without waiting
+func findIn(parent *rod.Element, reg *selectors.Registry, key selectors.Key) *rod.Element {
+	for _, c := range reg.Get(key) {
+		var found bool
+		var el *rod.Element
+		if c.Text == "" {
+			found, el, _ = parent.Has(c.CSS)
+		} else {
+			found, el, _ = parent.HasR(c.CSS, c.Text)
+		}
+		if found {
+			return el
+		}
+	}
+	return nil
+}
+
+// findAll returns every element matched by the first candidate of key that matches anything
+func findAll(page *rod.Page, reg *selectors.Registry, key selectors.Key) (rod.Elements, error) {
+	for _, c := range reg.Get(key) {
+		elements, err := page.Elements(c.CSS)
+		if err != nil {
+			return nil, err
+		}
+		if c.Text != "" {
+			elements = filterByText(elements, c.Text)
+		}
+		if len(elements) > 0 {
+			return elements, nil
+		}
+	}
+	return nil, nil
+}
+
+// matchCandidate returns the first element matching c, or nil
+func matchCandidate(page *rod.Page, c selectors.Candidate) *rod.Element {
+	var found bool
+	var el *rod.Element
+	if c.Text == "" {
+		found, el, _ = page.Has(c.CSS)
+	} else {
+		found, el, _ = page.HasR(c.CSS, c.Text)
+	}
+	if !found {
+		return nil
+	}
+	return el
+}
+
+// filterByText keeps the elements whose text matches the pattern
+func filterByText(elements rod.Elements, pattern string) rod.Elements {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	var matched rod.Elements
+	for _, el := range elements {
+		if text, err := el.Text(); err == nil && re.MatchString(text) {
+			matched = append(matched, el)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,184 @@
+package linkedin
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/stealth"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/go-rod/rod"
+)
+
+// InboxResult summarizes a SyncInbox run.
+type InboxResult struct {
+	Threads  int // Conversations opened
+	Matched  int // Conversations with a profile from the database
+	Messages int // Messages stored for the first time
+	Replied  int // Profiles newly marked 'replied'
+	OptedOut int // Profiles whose replies asked us to stop
+}
+
+// SyncInbox opens the inbox, walks the newest limit conversations and stores the threads with
+// profiles from the database (other conversations are skipped) in the conversations/messages tables.
+// Contacts who wrote anything are marked 'replied', which halts their follow-up sequence,
+// unless their replies ask us to stop: those are marked 'opted_out' instead.
+// With cfg.DryRun messages are still stored, but status changes are only recorded as dry-run results.
+// Stops before the next thread once guard.Proceed says so, or once a page shows an account warning.
+func SyncInbox(page *rod.Page, db *sql.DB, cfg *config.Config, limit int) (*InboxResult, error) {
+	ep, sel := cfg.Endpoints, cfg.Selectors
+	logger.Info("📬 Syncing inbox")
+
+	page.MustNavigate(ep.MessagingURL())
+	page.MustWaitLoad()
+	stealth.RandomSleep(3000, 5000)
+	if err := checkWarnings(page, sel); err != nil {
+		return nil, err
+	}
+
+	links, err := findAll(page, sel, selectors.InboxThreadLink)
+	if err != nil {
+		return nil, err
+	}
+	var threads []string
+	seen := make(map[string]bool)
+	for _, link := range links {
+		href, err := link.Property("href")
+		if err != nil {
+			continue
+		}
+		threadURL := href.String()
+		if !ep.IsMessagingURL(threadURL) || seen[threadURL] {
+			continue
+		}
+		seen[threadURL] = true
+		threads = append(threads, threadURL)
+		if len(threads) >= limit {
+			break
+		}
+	}
+	logger.Info("Found conversations to sync", "count", len(threads))
+
+	result := &InboxResult{}
+	for _, threadURL := range threads {
+		if err := guard.Proceed(); err != nil {
+			return result, err
+		}
+		result.Threads++
+		logger.Info("👉 Opening thread", "thread_url", threadURL)
+
+		page.MustNavigate(threadURL)
+		page.MustWaitLoad()
+		stealth.RandomSleep(2000, 4000)
+		if err := checkWarnings(page, sel); err != nil {
+			return result, err
+		}
+
+		profileURL := threadProfile(page, cfg)
+		if profileURL == "" {
+			logger.Warn("⚠️ No profile link in this thread. Skipping", "thread_url", threadURL)
+			continue
+		}
+		profile, err := storage.GetProfile(db, profileURL)
+		if err == sql.ErrNoRows {
+			logger.Info("⏭️ Profile is not in the database. Skipping", logging.Profile(profileURL))
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Matched++
+
+		messages := readThread(page, sel)
+		added, err := storage.SaveConversation(db, profile.URL, threadURL, messages)
+		if err != nil {
+			return result, err
+		}
+		result.Messages += added
+
+		incoming := 0
+		for _, m := range messages {
+			if m.Sender == storage.SenderContact {
+				incoming++
+			}
+		}
+		logger.Info("💾 Stored conversation", logging.Profile(profile.URL), "messages", len(messages), "from_contact", incoming, "new", added)
+		switch {
+		case incoming == 0, profile.Status == storage.StatusOptedOut, profile.Status == storage.StatusSuppressed:
+		case detectOptOut(page, db, cfg, profile.URL):
+			result.OptedOut++
+		case storage.CanTransition(profile.Status, storage.StatusReplied):
+			if markReplied(db, cfg, "inbox-sync", profile.URL, fmt.Sprintf("inbox-sync: %d message(s) from contact", incoming)) {
+				result.Replied++
+			}
+		}
+		stealth.RandomSleep(3000, 6000)
+	}
+
+	return result, nil
+}
+
+// threadProfile returns the canonical URL of the profile the open thread is with, or ""
+func threadProfile(page *rod.Page, cfg *config.Config) string {
+	el := matchFirst(page, cfg.Selectors, selectors.InboxThreadProfile)
+	if el == nil {
+		return ""
+	}
+	href, err := el.Property("href")
+	if err != nil || !cfg.Endpoints.IsProfileURL(href.String()) {
+		return ""
+	}
+	return storage.CanonicalURL(href.String())
+}
+
+// readThread returns the messages of the open thread, oldest first.
+// Sender name and timestamp are only shown on the first message of a group, so they carry over.
+func readThread(page *rod.Page, sel *selectors.Registry) []storage.ConversationMessage {
+	items, err := findAll(page, sel, selectors.InboxMessage)
+	if err != nil {
+		return nil
+	}
+
+	var messages []storage.ConversationMessage
+	var name, sentAt string
+	for _, item := range items {
+		if el := findIn(item, sel, selectors.InboxMessageSender); el != nil {
+			name = cleanText(el)
+		}
+		if el := findIn(item, sel, selectors.InboxMessageTime); el != nil {
+			sentAt = timestamp(el)
+		}
+		body := findIn(item, sel, selectors.InboxMessageBody)
+		if body == nil {
+			continue
+		}
+		text, err := body.Text()
+		if err != nil || strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		sender := storage.SenderMe
+		if findIn(item, sel, selectors.InboxMessageOther) != nil {
+			sender = storage.SenderContact
+		}
+		messages = append(messages, storage.ConversationMessage{
+			Sender:     sender,
+			SenderName: name,
+			SentAt:     sentAt,
+			Text:       strings.TrimSpace(text),
+		})
+	}
+	return messages
+}
+
+// timestamp prefers the machine-readable datetime attribute over the label shown
+func timestamp(el *rod.Element) string {
+	if dt, err := el.Attribute("datetime"); err == nil && dt != nil && *dt != "" {
+		return *dt
+	}
+	return cleanText(el)
+}
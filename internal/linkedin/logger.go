@@ -0,0 +1,16 @@
+package linkedin
+
+import (
+	"log/slog"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// logger receives the package's log records. main points it at the run's logger, tagged with
+// the run ID, mode and campaign.
+var logger logging.Logger
+
+// SetLogger sets the logger the linkedin package writes to.
+func SetLogger(l *slog.Logger) {
+	logger.Set(l)
+}
@@ -2,150 +2,254 @@ package linkedin
 
 import (
 	"database/sql"
-	"log"
-	"strings"
+	"fmt"
 	"time"
 
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
 	"github.com/SNKT2024/linkedin-automation/internal/stealth"
 	"github.com/SNKT2024/linkedin-automation/internal/storage"
 	"github.com/go-rod/rod"
 )
 
-// SendMessages checks profiles and sends a welcome message if connected
-func SendMessages(page *rod.Page, db *sql.DB, messageTemplate string, limit int) error {
-	log.Println("📨 Starting Messaging Service...")
+// SendMessages works through the follow-up sequence (cfg.Sequence): invited profiles are checked
+// for acceptance, and each profile gets at most the one step that is due now.
+// If the first step has a delay, accepting the invite only schedules it.
+// As soon as the contact has replied in the chat the sequence is halted for good.
+// Profiles on the do-not-contact list are marked 'suppressed' and skipped, and
+// profiles whose replies in the chat ask us to stop are marked 'opted_out' instead of messaged.
+// With cfg.DryRun the chat is opened and the message rendered, but nothing is typed or sent
+// and status changes are only recorded as dry-run results.
+// Every profile visit and message is reserved from budget first; as many profiles are checked as
+// the view budget allows. Stops before the next profile once guard.Proceed says so, and returns the
+// error once the budget refuses a visit or message or a page shows an account warning.
+func SendMessages(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget) error {
+	ep, sel := cfg.Endpoints, cfg.Selectors
+	logger.Info("📨 Starting Messaging Service")
+
+	// Every check needs a profile view; dry runs send nothing, so only real runs need room for a message
+	if err := budget.Reserve(storage.ActionProfileView); err != nil {
+		return err
+	}
+	if !cfg.DryRun {
+		if err := budget.Reserve(storage.ActionMessage); err != nil {
+			return err
+		}
+	}
 
 	// 1. Get profiles
-	profiles, err := storage.GetProfilesByStatus(db, "invited", limit)
-	if err != nil { return err }
+	limit, err := budget.Remaining(storage.ActionProfileView)
+	if err != nil {
+		return err
+	}
+	profiles, err := storage.GetProfilesDueForMessage(db, limit)
+	if err != nil {
+		return err
+	}
 
 	if len(profiles) == 0 {
-		log.Println("⚠️ No 'invited' profiles found to check. Run 'connect' mode first.")
+		logger.Info("⚠️ No profiles due for a follow-up. Run 'connect' mode first, or wait for the next step")
 		return nil
 	}
 
-	log.Printf("Found %d invited profiles to check for acceptance", len(profiles))
+	logger.Info("Found profiles to check for acceptance or a due follow-up", "count", len(profiles))
 
 	sentCount := 0
 
-	for _, profileURL := range profiles {
-		if sentCount >= limit {
-			log.Println("🛑 Message session limit reached.")
-			break
+	for _, profile := range profiles {
+		profileURL := profile.URL
+		if err := guard.Proceed(); err != nil {
+			return err
 		}
 
-		log.Printf("👉 Checking status for: %s", profileURL)
+		plog := logger.With(logging.Profile(profileURL))
+		plog.Info("👉 Checking status")
+
+		if !ep.IsProfileURL(profileURL) {
+			plog.Warn("⚠️ Not a profile URL on the configured site. Skipping", "base_url", ep.BaseURL)
+			continue
+		}
+		step := profile.SequenceStep
+		if step >= len(cfg.Sequence) {
+			// The sequence was shortened after this profile was scheduled
+			plog.Info("✅ All follow-up steps already sent", "steps", len(cfg.Sequence))
+			if !cfg.DryRun {
+				if err := storage.HaltSequence(db, profileURL, "message: no follow-up steps left"); err != nil {
+					plog.Warn("⚠️ Failed to halt sequence", logging.Err(err))
+				}
+			}
+			continue
+		}
+		if s, skip := checkSuppressed(db, profile); skip {
+			if s != nil {
+				suppress(db, cfg, "message", profileURL, s)
+			}
+			continue
+		}
 
 		// Navigate
-		page.MustNavigate(profileURL)
-		page.MustWaitLoad()
+		if err := visitProfile(page, db, sel, budget, profileURL, "message"); err != nil {
+			return err
+		}
 		stealth.RandomSleep(3000, 5000)
+		profile = refreshProfile(page, db, sel, profile)
+		if s, skip := checkSuppressed(db, profile); skip {
+			if s != nil {
+				suppress(db, cfg, "message", profileURL, s)
+			}
+			continue
+		}
 
 		// 2. DETECT CONNECTION STATUS
-		// Use Timeout for detection only
-		msgBtnSelector := "button, a"
-		foundMsgBtn, _, _ := page.Timeout(3 * time.Second).HasR(msgBtnSelector, "^Message$")
-		
-		if !foundMsgBtn {
-			if foundPending, _, _ := page.Timeout(2 * time.Second).HasR("button", "Pending|Withdraw"); foundPending {
-				log.Println("   ⏳ Still Pending. Skipping.")
-				storage.UpdateStatus(db, profileURL, "pending")
+		// find() returns elements bound to the original page, so they are safe to click
+		msgBtn, err := find(page, sel, selectors.ProfileMessage, 3*time.Second)
+
+		if err != nil {
+			if has(page, sel, selectors.ProfilePending, 2*time.Second) {
+				plog.Info("⏳ Still Pending. Skipping")
+				setStatus(db, cfg, "message", profileURL, storage.StatusPending, "message: invite not yet accepted")
 			} else {
-				log.Println("   ❌ Not connected (No 'Message' button). Skipping.")
+				plog.Info("❌ Not connected (No 'Message' button). Skipping")
 			}
 			continue
 		}
 
-		// Grab the button safely (without timeout) to check attributes/click
-		// We use Elements() and filter manually to be safe, or just FindR if confident
-		msgBtn, err := page.ElementR(msgBtnSelector, "^Message$")
-		if err != nil { continue }
-
 		// Check for locked Premium InMail icon
-		if lockIcon, _ := msgBtn.Element("svg[data-test-icon='lock-small']"); lockIcon != nil {
-			log.Println("   🔒 Message button is locked (Premium only). Skipping.")
-			storage.UpdateStatus(db, profileURL, "premium_only")
+		if lockIcon := findIn(msgBtn, sel, selectors.ProfileMessageLock); lockIcon != nil {
+			plog.Info("🔒 Message button is locked (Premium only). Skipping")
+			setStatus(db, cfg, "message", profileURL, storage.StatusPremiumOnly, "message: message button locked")
 			continue
 		}
 
-		log.Println("   ✅ Message button found. Clicking...")
+		plog.Debug("✅ Message button found. Clicking")
 		stealth.HumanClick(page, msgBtn)
 		stealth.RandomSleep(2000, 3000)
 
 		// 3. PRIORITY CHECK: DID THE CHAT BOX OPEN?
-		// Selector for the chat box
-		chatSelector := "div[role='textbox'][aria-label*='Write a message']"
-		
 		// Wait up to 5 seconds for it to appear
-		if found, _, _ := page.Timeout(5 * time.Second).Has(chatSelector); found {
+		if chatBox, err := find(page, sel, selectors.MessageChatInput, 5*time.Second); err == nil {
 			// === SUCCESS PATH: CHAT IS OPEN ===
-			log.Println("   ✅ Chat input found! Connection active.")
-			
-			// CRITICAL FIX: Grab the element using the original 'page' (no timeout)
-			// This prevents the "Context Deadline Exceeded" panic while typing
-			chatBox := page.MustElement(chatSelector)
-
-			// Personalize
-			firstName := "there"
-			if nameEl, err := page.Timeout(2 * time.Second).Element("h1"); err == nil {
-				text := nameEl.MustText()
-				parts := strings.Split(text, " ")
-				if len(parts) > 0 { firstName = parts[0] }
+			// find() resolves on the original 'page' (no timeout), which prevents
+			// the "Context Deadline Exceeded" panic while typing
+			plog.Info("✅ Chat input found. Connection active")
+
+			// Never message someone whose reply to the invite asked us to stop
+			stealth.RandomSleep(1000, 2000)
+			if detectOptOut(page, db, cfg, profileURL) {
+				closeChat(page, sel)
+				continue
+			}
+
+			// Never follow up on a conversation the contact already answered
+			if haltOnReply(page, db, cfg, profileURL) {
+				closeChat(page, sel)
+				continue
+			}
+
+			next := cfg.Sequence[step]
+			if step == 0 && next.Delay > 0 && !profile.NextActionAt.Valid {
+				due := time.Now().Add(next.Delay)
+				plog.Info("🗓️ Invite accepted. First follow-up scheduled", "due", due)
+				if cfg.DryRun {
+					recordDryRun(db, profileURL, "message", "would_schedule", "first follow-up due "+due.Format(time.RFC3339))
+				} else if err := storage.ScheduleNextAction(db, profileURL, due); err != nil {
+					plog.Warn("⚠️ Failed to schedule follow-up", logging.Err(err))
+				}
+				closeChat(page, sel)
+				continue
+			}
+
+			// Personalize from the stored (just refreshed) profile
+			finalMsg := personalize(next.Template, profile)
+			if finalMsg == "" {
+				plog.Warn("⚠️ Message rendered empty. Skipping")
+				closeChat(page, sel)
+				continue
+			}
+
+			if cfg.DryRun {
+				plog.Info("🧪 [DRY RUN] Would type", "step", step+1, "text", finalMsg)
+				if has(page, sel, selectors.MessageSend, 3*time.Second) {
+					plog.Info("🧪 [DRY RUN] Would click Send")
+				} else {
+					plog.Warn("⚠️ Could not find Send button")
+				}
+				recordDryRun(db, profileURL, "message", fmt.Sprintf("would_message_step_%d", step+1), finalMsg)
+				sentCount++
+				closeChat(page, sel)
+				continue
+			}
+
+			if err := budget.Reserve(storage.ActionMessage); err != nil {
+				closeChat(page, sel)
+				return err
 			}
-			finalMsg := strings.ReplaceAll(messageTemplate, "{firstName}", firstName)
 
 			// Type & Send (Now safe from timeouts)
-			log.Printf("   ✍️ Typing: '%s...'", finalMsg)
+			plog.Info("✍️ Typing follow-up", "step", step+1, "steps", len(cfg.Sequence), "text", finalMsg)
 			stealth.HumanType(chatBox, finalMsg)
 			stealth.RandomSleep(2000, 3000)
 
 			// Find Send Button
-			if sendBtn, err := page.Timeout(3 * time.Second).Element("button[type='submit']"); err == nil {
-				log.Println("   🚀 Clicking Send...")
+			if sendBtn, err := find(page, sel, selectors.MessageSend, 3*time.Second); err == nil {
+				plog.Info("🚀 Clicking Send")
 				stealth.HumanClick(page, sendBtn)
 				stealth.RandomSleep(2000, 3000)
-				
-				storage.UpdateStatus(db, profileURL, "messaged")
-				log.Println("   ✅ Message sent & DB updated.")
+
+				var nextAt sql.NullTime
+				if step+1 < len(cfg.Sequence) {
+					nextAt = sql.NullTime{Time: time.Now().Add(cfg.Sequence[step+1].Delay), Valid: true}
+				}
+				recordOutbound(db, profileURL, storage.OutboundFollowup, next.Template, finalMsg)
+				recordAction(db, storage.ActionMessage, profileURL, 1, next.Template.Name())
+				reason := fmt.Sprintf("message: follow-up step %d/%d sent", step+1, len(cfg.Sequence))
+				if err := storage.AdvanceSequence(db, profileURL, step+1, nextAt, reason); err != nil {
+					plog.Warn("⚠️ Failed to record follow-up step", logging.Err(err))
+				}
+				plog.Info("✅ Message sent & DB updated", "step", step+1)
 				sentCount++
+				if err := checkWarnings(page, sel); err != nil {
+					return err
+				}
 
 				// === ☕ NEW: COFFEE BREAK LOGIC ===
-            // After every 3 messages, take a break
-            if sentCount > 0 && sentCount%3 == 0 {
-                log.Println("   ☕ Taking a short break to mimic human behavior...")
-                stealth.RandomSleep(45000, 90000) // 45s - 90s
-                continue
-            }
-            // ==================================
+				// After every 3 messages, take a break
+				if sentCount > 0 && sentCount%3 == 0 {
+					logger.Info("☕ Taking a short break to mimic human behavior")
+					stealth.RandomSleep(45000, 90000) // 45s - 90s
+					continue
+				}
+				// ==================================
 			} else {
-				log.Println("   ⚠️ Could not find Send button.")
+				plog.Warn("⚠️ Could not find Send button")
 			}
 
-			closeChat(page)
+			closeChat(page, sel)
 
 		} else {
 			// === FAILURE PATH: CHAT DID NOT OPEN ===
-			log.Println("   ⚠️ Chat box did not appear. Checking for Premium Popup...")
-			
+			plog.Info("⚠️ Chat box did not appear. Checking for Premium Popup")
+
 			// Check for popup (Wait 2s)
-			popupSelector := "div[role='dialog'], div.artdeco-modal"
-			if foundPopup, _, _ := page.Timeout(2 * time.Second).HasR(popupSelector, "Message with Premium|Try Premium|Unlock InMail"); foundPopup {
-				log.Println("   🛑 Blocked by Premium/InMail Popup. (Not fully connected).")
-				
+			if has(page, sel, selectors.MessagePremiumPopup, 2*time.Second) {
+				plog.Info("🛑 Blocked by Premium/InMail Popup (Not fully connected)")
+
 				// Close popup
-				if closeBtn, err := page.Timeout(2 * time.Second).Element(`button[aria-label="Dismiss"], button[aria-label="Close"]`); err == nil {
+				if closeBtn, err := find(page, sel, selectors.MessagePopupClose, 2*time.Second); err == nil {
 					closeBtn.MustClick()
 				} else {
 					page.Keyboard.Press(27) // Escape
 				}
-				
-				storage.UpdateStatus(db, profileURL, "pending")
+
+				setStatus(db, cfg, "message", profileURL, storage.StatusPending, "message: premium popup instead of chat")
 			} else {
-				log.Println("   ❌ Unknown state: Clicked message but no chat and no popup.")
+				plog.Warn("❌ Unknown state: Clicked message but no chat and no popup")
 			}
 		}
 
-		log.Println("   ❄️ Cooling down...")
+		logger.Debug("❄️ Cooling down")
 		stealth.RandomSleep(5000, 10000)
 	}
 
@@ -153,10 +257,10 @@ func SendMessages(page *rod.Page, db *sql.DB, messageTemplate string, limit int)
 }
 
 // Helper to close chat windows
-func closeChat(page *rod.Page) {
-	if closeBtn, err := page.Timeout(2 * time.Second).Element(`button[aria-label*="Close"]`); err == nil {
+func closeChat(page *rod.Page, sel *selectors.Registry) {
+	if closeBtn, err := find(page, sel, selectors.MessageChatClose, 2*time.Second); err == nil {
 		if visible, _ := closeBtn.Visible(); visible {
 			closeBtn.MustClick()
 		}
 	}
-}
\ No newline at end of file
+}
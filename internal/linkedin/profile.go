@@ -0,0 +1,198 @@
+package linkedin
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/SNKT2024/linkedin-automation/internal/template"
+	"github.com/go-rod/rod"
+)
+
+// ScrapeProfile reads the identity fields from the profile page currently open.
+// Fields that cannot be found are left empty.
+func ScrapeProfile(page *rod.Page, sel *selectors.Registry) storage.ProfileDetails {
+	var d storage.ProfileDetails
+	if el, err := find(page, sel, selectors.ProfileName, 2*time.Second); err == nil {
+		d.FullName = cleanText(el)
+	}
+	d.FirstName, d.LastName = SplitName(d.FullName)
+
+	if el := matchFirst(page, sel, selectors.ProfileHeadline); el != nil {
+		d.Headline = cleanText(el)
+	}
+	if el := matchFirst(page, sel, selectors.ProfileLocation); el != nil {
+		d.Location = cleanText(el)
+	}
+	if el := matchFirst(page, sel, selectors.ProfileCompany); el != nil {
+		d.Company = cleanText(el)
+	}
+	if d.Company == "" {
+		d.Company = companyFromHeadline(d.Headline)
+	}
+	return d
+}
+
+// refreshProfile scrapes the open profile page, stores the details and returns the merged record.
+// Falls back to the record passed in if the database cannot be read.
+func refreshProfile(page *rod.Page, db *sql.DB, sel *selectors.Registry, profile storage.Profile) storage.Profile {
+	details := ScrapeProfile(page, sel)
+	if err := storage.UpdateProfileDetails(db, profile.URL, details); err != nil {
+		logger.Warn("⚠️ Could not save profile details", logging.Profile(profile.URL), logging.Err(err))
+	}
+	if fresh, err := storage.GetProfile(db, profile.URL); err == nil {
+		return *fresh
+	}
+	return profile
+}
+
+// scrapeCard reads the identity fields from a search result card
+func scrapeCard(card *rod.Element, sel *selectors.Registry) storage.ProfileDetails {
+	var d storage.ProfileDetails
+	if el := findIn(card, sel, selectors.SearchCardName); el != nil {
+		d.FullName = cleanText(el)
+	}
+	d.FirstName, d.LastName = SplitName(d.FullName)
+	if el := findIn(card, sel, selectors.SearchCardHeadline); el != nil {
+		d.Headline = cleanText(el)
+	}
+	if el := findIn(card, sel, selectors.SearchCardLocation); el != nil {
+		d.Location = cleanText(el)
+	}
+	d.Company = companyFromHeadline(d.Headline)
+	return d
+}
+
+// SplitName splits a display name into first name and the remainder.
+func SplitName(fullName string) (first, last string) {
+	parts := strings.Fields(fullName)
+	if len(parts) == 0 {
+		return "", ""
+	}
+	return parts[0], strings.Join(parts[1:], " ")
+}
+
+// companyFromHeadline extracts "Acme" from headlines like "Engineer at Acme"
+func companyFromHeadline(headline string) string {
+	for _, sep := range []string{" at ", " @ "} {
+		if idx := strings.LastIndex(headline, sep); idx != -1 {
+			company := headline[idx+len(sep):]
+			if cut := strings.IndexAny(company, "|·,"); cut != -1 {
+				company = company[:cut]
+			}
+			return strings.TrimSpace(company)
+		}
+	}
+	return ""
+}
+
+// matchFirst returns the first element for key without waiting
+func matchFirst(page *rod.Page, sel *selectors.Registry, key selectors.Key) *rod.Element {
+	el, err := find(page, sel, key, 0)
+	if err != nil {
+		return nil
+	}
+	return el
+}
+
+// cleanText returns the element text on a single trimmed line
+func cleanText(el *rod.Element) string {
+	text, err := el.Text()
+	if err != nil {
+		return ""
+	}
+	if idx := strings.Index(text, "\n"); idx != -1 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
+
+// fullText returns the whole element text with every run of whitespace, line breaks included,
+// collapsed to one space. Use it for text that is classified; cleanText is for display.
+func fullText(el *rod.Element) string {
+	text, err := el.Text()
+	if err != nil {
+		return ""
+	}
+	return collapseSpace(text)
+}
+
+// collapseSpace trims text and collapses every run of whitespace to one space
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// personalize renders tmpl for the stored profile. A render error is logged and yields ""
+// (a connection request is then sent without a note, a message is skipped).
+func personalize(tmpl *template.Template, profile storage.Profile) string {
+	text, err := tmpl.Render(template.Data{
+		FirstName: profile.FirstName,
+		LastName:  profile.LastName,
+		FullName:  profile.FullName,
+		Company:   profile.Company,
+		Headline:  profile.Headline,
+		Location:  profile.Location,
+	})
+	if err != nil {
+		logger.Warn("⚠️ Failed to render template", logging.Profile(profile.URL), "template", tmpl.Name(), logging.Err(err))
+		return ""
+	}
+	return text
+}
+
+// recordOutbound stores the exact text sent to a profile and the template it came from
+func recordOutbound(db *sql.DB, profileURL string, kind storage.OutboundKind, tmpl *template.Template, text string) {
+	if err := storage.RecordOutbound(db, profileURL, kind, tmpl.Name(), text); err != nil {
+		logger.Warn("⚠️ Failed to record sent text", logging.Profile(profileURL), "kind", kind, logging.Err(err))
+	}
+}
+
+// visitProfile opens a profile page if the budget allows another profile view, records the view
+// and checks the page for account warnings. Any error means the run must not act on the page:
+// the budget refused the view (guard.ErrBudgetExhausted) or the breaker tripped (guard.ErrTripped).
+func visitProfile(page *rod.Page, db *sql.DB, sel *selectors.Registry, budget *guard.Budget, profileURL, detail string) error {
+	if err := budget.Reserve(storage.ActionProfileView); err != nil {
+		return err
+	}
+	page.MustNavigate(profileURL)
+	page.MustWaitLoad()
+	recordAction(db, storage.ActionProfileView, profileURL, 1, detail)
+	return checkWarnings(page, sel)
+}
+
+// recordAction appends an outbound action to the ledger the rate limits count
+func recordAction(db *sql.DB, kind storage.ActionKind, profileURL string, count int, detail string) {
+	if err := storage.RecordAction(db, kind, profileURL, count, detail); err != nil {
+		logger.Warn("⚠️ Failed to record action", logging.Profile(profileURL), "kind", kind, logging.Err(err))
+	}
+}
+
+// checkSuppressed reports whether profile must be skipped and the do-not-contact entry it matches.
+// A failed lookup also skips (with a nil entry): we never contact someone we could not check.
+func checkSuppressed(db *sql.DB, profile storage.Profile) (*storage.Suppression, bool) {
+	s, err := storage.CheckSuppressed(db, profile)
+	if err != nil {
+		logger.Warn("⚠️ Suppression check failed. Skipping", logging.Profile(profile.URL), logging.Err(err))
+		return nil, true
+	}
+	if s != nil {
+		logger.Info("🚫 Profile is on the suppression list. Skipping", logging.Profile(profile.URL), "entry", s.String())
+	}
+	return s, s != nil
+}
+
+// suppress marks a profile matching the do-not-contact list as 'suppressed' (recorded only in dry-run mode)
+func suppress(db *sql.DB, cfg *config.Config, action, profileURL string, s *storage.Suppression) {
+	if cfg.DryRun {
+		recordDryRun(db, profileURL, action, string(storage.StatusSuppressed), s.String())
+		return
+	}
+	if err := storage.SuppressProfile(db, profileURL, s, action); err != nil {
+		logger.Warn("⚠️ Failed to suppress profile", logging.Profile(profileURL), logging.Err(err))
+	}
+}
@@ -0,0 +1,73 @@
+package linkedin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SNKT2024/linkedin-automation/internal/template"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		full, first, last string
+	}{
+		{"Jane Doe", "Jane", "Doe"},
+		{"  Jane   van der Berg ", "Jane", "van der Berg"},
+		{"Cher", "Cher", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		first, last := SplitName(tt.full)
+		if first != tt.first || last != tt.last {
+			t.Errorf("SplitName(%q) = %q, %q; want %q, %q", tt.full, first, last, tt.first, tt.last)
+		}
+	}
+}
+
+func TestCompanyFromHeadline(t *testing.T) {
+	tests := []struct {
+		headline, want string
+	}{
+		{"Software Engineer at Acme", "Acme"},
+		{"Engineer at Initech | ex-Acme", "Initech"},
+		{"Founder @ Hooli, Palo Alto", "Hooli"},
+		{"Head of Data at Foo at Bar", "Bar"},
+		{"Freelance developer", ""},
+	}
+	for _, tt := range tests {
+		if got := companyFromHeadline(tt.headline); got != tt.want {
+			t.Errorf("companyFromHeadline(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
+
+func TestCollapseSpace(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Thanks,\nbut please stop\n\nmessaging me", "Thanks, but please stop messaging me"},
+		{"  \t padded  text  ", "padded text"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := collapseSpace(tt.text); got != tt.want {
+			t.Errorf("collapseSpace(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNoteText(t *testing.T) {
+	long := strings.Repeat("é", template.ConnectNoteLimit+5)
+	tests := []struct {
+		message string
+		want    int // Runes kept
+	}{
+		{"Hi Jane", 7},
+		{long, template.ConnectNoteLimit},
+	}
+	for _, tt := range tests {
+		if got := len([]rune(noteText(tt.message))); got != tt.want {
+			t.Errorf("noteText kept %d runes of %d, want %d", got, len([]rune(tt.message)), tt.want)
+		}
+	}
+}
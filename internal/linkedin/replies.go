@@ -0,0 +1,154 @@
+package linkedin
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/SNKT2024/linkedin-automation/internal/stealth"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/go-rod/rod"
+)
+
+// latestReplies is how many of the newest incoming messages are classified
+const latestReplies = 5
+
+// readReplies returns the full text of messages the other person sent in the open chat, oldest first,
+// so a multi-line reply is classified as a whole
+func readReplies(page *rod.Page, sel *selectors.Registry) []string {
+	elements, err := findAll(page, sel, selectors.MessageIncoming)
+	if err != nil {
+		return nil
+	}
+	var replies []string
+	for _, el := range elements {
+		if text := fullText(el); text != "" {
+			replies = append(replies, text)
+		}
+	}
+	return replies
+}
+
+// detectOptOut classifies the latest replies in the open chat and, if one asks us to stop,
+// moves the profile to 'opted_out' (only recorded in dry-run mode). Returns true if the profile opted out.
+func detectOptOut(page *rod.Page, db *sql.DB, cfg *config.Config, profileURL string) bool {
+	replies := readReplies(page, cfg.Selectors)
+	if len(replies) > latestReplies {
+		replies = replies[len(replies)-latestReplies:]
+	}
+
+	for i := len(replies) - 1; i >= 0; i-- {
+		rule, ok := cfg.OptOut.Match(replies[i])
+		if !ok {
+			continue
+		}
+		plog := logger.With(logging.Profile(profileURL))
+		plog.Info("🙅 Opt-out reply detected", "rule", rule, "reply", preview(replies[i], 80))
+		reason := fmt.Sprintf("reply matched %q", rule)
+		if cfg.DryRun {
+			recordDryRun(db, profileURL, "message", string(storage.StatusOptedOut), reason)
+			return true
+		}
+		if err := storage.MarkOptedOut(db, profileURL, reason); err != nil {
+			plog.Warn("⚠️ Failed to record opt-out", logging.Err(err))
+		}
+		return true
+	}
+	return false
+}
+
+// haltOnReply marks the profile 'replied', which halts its follow-up sequence, if the open chat
+// has any message from the contact. Returns true if there was a reply.
+func haltOnReply(page *rod.Page, db *sql.DB, cfg *config.Config, profileURL string) bool {
+	replies := readReplies(page, cfg.Selectors)
+	if len(replies) == 0 {
+		return false
+	}
+	logger.Info("💬 Contact replied. Halting follow-up sequence", logging.Profile(profileURL), "replies", len(replies))
+	markReplied(db, cfg, "message", profileURL, fmt.Sprintf("message: reply detected: %q", preview(replies[len(replies)-1], 40)))
+	return true
+}
+
+// markReplied moves the profile to 'replied' (only recorded in dry-run mode). A profile whose
+// status cannot become 'replied' still gets its sequence halted. Returns true if the status changed.
+func markReplied(db *sql.DB, cfg *config.Config, action, profileURL, reason string) bool {
+	if cfg.DryRun {
+		recordDryRun(db, profileURL, action, string(storage.StatusReplied), reason)
+		return true
+	}
+	changed, err := storage.MarkReplied(db, profileURL, reason)
+	if err != nil {
+		logger.Warn("⚠️ Failed to record reply", logging.Profile(profileURL), logging.Err(err))
+		return false
+	}
+	if !changed {
+		if err := storage.HaltSequence(db, profileURL, reason); err != nil {
+			logger.Warn("⚠️ Failed to halt sequence", logging.Profile(profileURL), logging.Err(err))
+		}
+	}
+	return changed
+}
+
+// CheckReplies opens the chat of up to limit messaged profiles and marks those whose
+// latest replies ask us to stop as 'opted_out'. Any other reply marks the profile 'replied'.
+// Returns how many opted out. Stops before the next profile once guard.Proceed says so,
+// and once budget refuses another profile view or a profile shows an account warning.
+func CheckReplies(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget, limit int) (int, error) {
+	ep, sel := cfg.Endpoints, cfg.Selectors
+	logger.Info("📥 Checking replies for opt-outs")
+
+	profiles, err := storage.GetProfilesForReplyCheck(db, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(profiles) == 0 {
+		logger.Info("No messaged profiles to check")
+		return 0, nil
+	}
+
+	optedOut := 0
+	for _, profile := range profiles {
+		if !ep.IsProfileURL(profile.URL) {
+			continue
+		}
+		if err := guard.Proceed(); err != nil {
+			return optedOut, err
+		}
+		plog := logger.With(logging.Profile(profile.URL))
+		plog.Info("👉 Reading thread")
+
+		if err := visitProfile(page, db, sel, budget, profile.URL, "reply-check"); err != nil {
+			return optedOut, err
+		}
+		stealth.RandomSleep(3000, 5000)
+		refreshProfile(page, db, sel, profile)
+
+		msgBtn, err := find(page, sel, selectors.ProfileMessage, 3*time.Second)
+		if err != nil || findIn(msgBtn, sel, selectors.ProfileMessageLock) != nil {
+			plog.Info("⚠️ No usable 'Message' button. Skipping")
+			continue
+		}
+		stealth.HumanClick(page, msgBtn)
+
+		if _, err := find(page, sel, selectors.MessageChatInput, 5*time.Second); err != nil {
+			plog.Warn("⚠️ Chat box did not appear. Skipping")
+			continue
+		}
+		stealth.RandomSleep(1500, 3000)
+
+		if detectOptOut(page, db, cfg, profile.URL) {
+			optedOut++
+		} else {
+			haltOnReply(page, db, cfg, profile.URL)
+		}
+		closeChat(page, sel)
+		stealth.RandomSleep(5000, 10000)
+	}
+
+	logger.Info("📥 Reply check complete", "opted_out", optedOut, "checked", len(profiles))
+	return optedOut, nil
+}
@@ -3,58 +3,53 @@ package linkedin
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/SNKT2024/linkedin-automation/internal/config"
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
 	"github.com/SNKT2024/linkedin-automation/internal/stealth"
 	"github.com/SNKT2024/linkedin-automation/internal/storage"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 )
 
-// SearchPeople orchestrates the search workflow
-func SearchPeople(page *rod.Page, db *sql.DB, keyword string, maxPages int) ([]string, error) {
-	log.Printf("🔍 Searching for people with keyword: '%s'", keyword)
+// SearchPeople orchestrates the search workflow.
+// cfg.Endpoints decides where the feed lives and which links count as profile URLs.
+// Stops before the next results page once guard.Proceed says so, once budget has no room left
+// for the profiles it collects, or once a page shows an account warning (returning the error,
+// with the profiles saved so far).
+func SearchPeople(page *rod.Page, db *sql.DB, cfg *config.Config, budget *guard.Budget, keyword string, maxPages int) ([]string, error) {
+	ep, sel := cfg.Endpoints, cfg.Selectors
+	if err := budget.Reserve(storage.ActionSearchPage); err != nil {
+		return nil, err
+	}
+	logger.Info("🔍 Searching for people", "keyword", keyword, "max_pages", maxPages)
 
 	// === CRITICAL FIX: Wait for Feed to Settle ===
-	// This prevents the bot from checking for the search bar 
+	// This prevents the bot from checking for the search bar
 	// while the page is still white/loading after login.
-	log.Println("   ⏳ Waiting for feed to render...")
+	logger.Debug("⏳ Waiting for feed to render")
 	page.MustWaitLoad()
 	stealth.RandomSleep(3000, 5000)
 	// =============================================
 
 	// 1. Navigation (Safety check)
-	if !strings.Contains(page.MustInfo().URL, "/feed/") {
-		log.Println("   🔄 Navigating to Feed...")
-		page.MustNavigate("https://www.linkedin.com/feed/")
+	if !ep.IsFeedURL(page.MustInfo().URL) {
+		logger.Debug("🔄 Navigating to Feed")
+		page.MustNavigate(ep.FeedURL())
 		page.MustWaitLoad()
 		stealth.RandomSleep(3000, 5000)
 	}
 
 	// 2. Search Bar (Safe Find Pattern)
-	log.Println("🔍 Looking for search bar...")
-	
-	// We check for multiple possible selectors to be robust
-	searchSelectors := []string{"input.search-global-typeahead__input", "input[placeholder*='Search']"}
-	var searchInput *rod.Element
-	var found bool
-
-	// Try finding it for up to 10 seconds
-	for i := 0; i < 5; i++ {
-		for _, sel := range searchSelectors {
-			if has, _, _ := page.Has(sel); has {
-				searchInput = page.MustElement(sel)
-				found = true
-				break
-			}
-		}
-		if found { break }
-		time.Sleep(2 * time.Second)
-	}
+	logger.Debug("🔍 Looking for search bar")
 
-	if !found {
+	// The registry lists several fallbacks to be robust; try for up to 10 seconds
+	searchInput, err := find(page, sel, selectors.SearchInput, 10*time.Second)
+	if err != nil {
 		return nil, fmt.Errorf("could not find search bar within 10s")
 	}
 
@@ -62,8 +57,8 @@ func SearchPeople(page *rod.Page, db *sql.DB, keyword string, maxPages int) ([]s
 	searchInput.MustClick()
 	stealth.RandomSleep(500, 1000)
 	humanTypeWithMistakes(searchInput, keyword)
-	
-	log.Println("⌨️ Pressing Enter...")
+
+	logger.Debug("⌨️ Pressing Enter")
 	searchInput.MustType(input.Enter)
 	page.MustWaitLoad()
 	stealth.RandomSleep(4000, 6000)
@@ -71,11 +66,10 @@ func SearchPeople(page *rod.Page, db *sql.DB, keyword string, maxPages int) ([]s
 	// 3. People Filter
 	// Only click if we aren't already on the people tab
 	if !strings.Contains(page.MustInfo().URL, "/people/") {
-		log.Println("👥 Checking 'People' filter...")
-		
+		logger.Debug("👥 Checking 'People' filter")
+
 		// Try finding the button by text "People"
-		if found, _, _ := page.Timeout(5 * time.Second).HasR("button", "People"); found {
-			btn := page.MustElementR("button", "People")
+		if btn, err := find(page, sel, selectors.SearchPeopleFilter, 5*time.Second); err == nil {
 			// Only click if not already active (pressed)
 			if pressed, _ := btn.Attribute("aria-pressed"); pressed == nil || *pressed != "true" {
 				btn.MustClick()
@@ -88,97 +82,148 @@ func SearchPeople(page *rod.Page, db *sql.DB, keyword string, maxPages int) ([]s
 	var newProfiles []string
 
 	for pageNum := 1; pageNum <= maxPages; pageNum++ {
-		log.Printf("\n========== Page %d/%d ==========", pageNum, maxPages)
+		if err := guard.Proceed(); err != nil {
+			return newProfiles, err
+		}
+		if err := checkWarnings(page, sel); err != nil {
+			return newProfiles, err
+		}
+		logger.Info("📄 Results page", "page", pageNum, "max_pages", maxPages)
 
 		// 4. Check for Blocking Modals (Safe Check)
-		if found, _, _ := page.Timeout(2 * time.Second).HasR("button", "Got it|Close"); found {
-			log.Println("⚠️ Dismissing blocking modal...")
-			page.MustElementR("button", "Got it|Close").MustClick()
+		if modalBtn, err := find(page, sel, selectors.SearchModalDismiss, 2*time.Second); err == nil {
+			logger.Info("⚠️ Dismissing blocking modal")
+			modalBtn.MustClick()
 			stealth.RandomSleep(1000, 2000)
 		}
 
 		// 5. Smart Scroll
-		log.Println("📜 Scrolling to load results...")
+		logger.Debug("📜 Scrolling to load results")
 		SmartScroll(page)
 
 		// 6. Extraction
-		log.Println("📥 Scanning page for profile links...")
-		elements, err := page.Elements("a")
+		// Prefer result cards (URL + name/headline/location); fall back to scanning every link.
+		logger.Debug("📥 Scanning page for profile links")
+		results, err := collectResults(page, sel)
 		if err != nil {
-			log.Printf("❌ Error scanning page: %v", err)
+			logger.Error("❌ Error scanning page", "page", pageNum, logging.Err(err))
 			continue
 		}
 
 		count := 0
+		rank := 0
 		uniqueOnPage := make(map[string]bool)
 
-		for _, el := range elements {
-			link, err := el.Property("href")
-			if err != nil { continue }
+		for _, result := range results {
+			link, err := result.link.Property("href")
+			if err != nil {
+				continue
+			}
 			urlStr := link.String()
 
-			if strings.Contains(urlStr, "linkedin.com/in/") && 
-			   !strings.Contains(urlStr, "/minis/") &&
-			   !strings.Contains(urlStr, "google.com") {
-				
-				if idx := strings.Index(urlStr, "?"); idx != -1 { urlStr = urlStr[:idx] }
-				if uniqueOnPage[urlStr] { continue }
+			if ep.IsProfileURL(urlStr) &&
+				!strings.Contains(urlStr, "/minis/") &&
+				!strings.Contains(urlStr, "google.com") {
+
+				urlStr = storage.CanonicalURL(urlStr)
+				if uniqueOnPage[urlStr] {
+					continue
+				}
 				uniqueOnPage[urlStr] = true
-				
+				rank++
+
 				// Skip yourself if needed (optional)
 				// if strings.Contains(urlStr, "sanket-kumbhar") { continue }
 
-				added, _ := storage.AddProfile(db, urlStr)
+				added, _ := storage.AddProfile(db, storage.Profile{
+					URL:            urlStr,
+					ProfileDetails: result.details,
+					SearchKeyword:  keyword,
+					SearchPage:     pageNum,
+					SearchRank:     rank,
+				})
 				if added {
 					newProfiles = append(newProfiles, urlStr)
 					count++
 				}
 			}
 		}
-		log.Printf("💾 Saved %d NEW profiles from this page", count)
+		logger.Info("💾 Saved new profiles from this page", "page", pageNum, "count", count)
+		recordAction(db, storage.ActionSearchPage, "", count, fmt.Sprintf("%s (page %d)", keyword, pageNum))
 
 		// 7. Pagination (Next Button)
 		if pageNum < maxPages {
-			log.Println("➡️ Looking for 'Next' button...")
-			
-			// Try Primary Selector (Desktop)
-			if found, _, _ := page.Timeout(3 * time.Second).Has(`button[aria-label="Next"]`); found {
-				nextBtn := page.MustElement(`button[aria-label="Next"]`)
+			if err := budget.Reserve(storage.ActionSearchPage); err != nil {
+				return newProfiles, err
+			}
+			logger.Debug("➡️ Looking for 'Next' button")
+
+			// Registry tries the desktop selector first, then the text fallback
+			if nextBtn, err := find(page, sel, selectors.SearchNext, 3*time.Second); err == nil {
 				clickNext(page, nextBtn)
 			} else {
-				// Fallback Text Selector
-				if foundFallback, _, _ := page.Timeout(2 * time.Second).HasR("button, span", "^Next$"); foundFallback {
-					nextBtn := page.MustElementR("button, span", "^Next$")
-					clickNext(page, nextBtn)
-				} else {
-					log.Println("🛑 No 'Next' button found. End of search.")
-					break
-				}
+				logger.Info("🛑 No 'Next' button found. End of search", "page", pageNum)
+				break
 			}
 		}
 	}
 	return newProfiles, nil
 }
 
+// searchResult is a profile link on a results page plus whatever its card revealed
+type searchResult struct {
+	link    *rod.Element
+	details storage.ProfileDetails
+}
+
+// collectResults returns the profile links on the current results page.
+// Result cards give name, headline and location; without cards every link is returned bare.
+func collectResults(page *rod.Page, sel *selectors.Registry) ([]searchResult, error) {
+	cards, err := findAll(page, sel, selectors.SearchResultCard)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, card := range cards {
+		if link := findIn(card, sel, selectors.SearchResultLink); link != nil {
+			results = append(results, searchResult{link: link, details: scrapeCard(card, sel)})
+		}
+	}
+	if len(results) > 0 {
+		return results, nil
+	}
+
+	logger.Warn("⚠️ No result cards matched. Falling back to plain link scan")
+	links, err := findAll(page, sel, selectors.SearchResultLink)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		results = append(results, searchResult{link: link})
+	}
+	return results, nil
+}
+
 // Helper to safely click next
 func clickNext(page *rod.Page, btn *rod.Element) {
 	// Check visibility before scrolling
 	if visible, _ := btn.Visible(); !visible {
-		log.Println("⚠️ Next button found but hidden.")
+		logger.Warn("⚠️ Next button found but hidden")
 		return
 	}
-	
+
 	btn.MustScrollIntoView()
 	stealth.RandomSleep(500, 1000)
-	
-	log.Println("👆 Clicking Next...")
+
+	logger.Debug("👆 Clicking Next")
 	stealth.HumanClick(page, btn)
 	page.MustWaitLoad()
 	stealth.RandomSleep(4000, 6000)
 }
 
 func humanTypeWithMistakes(element *rod.Element, text string) {
-	log.Printf("⌨️ Typing: '%s'", text)
+	logger.Debug("⌨️ Typing", "text", text)
 	for _, char := range text {
 		element.MustInput(string(char))
 		stealth.RandomSleep(80, 200)
@@ -195,4 +240,4 @@ func SmartScroll(page *rod.Page) {
 	// Final JS nudge to ensure we hit the footer
 	page.MustEval(`() => window.scrollTo({ top: document.body.scrollHeight, behavior: 'smooth' })`)
 	stealth.RandomSleep(2000, 3000)
-}
\ No newline at end of file
+}
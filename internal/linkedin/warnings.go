@@ -0,0 +1,36 @@
+package linkedin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SNKT2024/linkedin-automation/internal/guard"
+	"github.com/SNKT2024/linkedin-automation/internal/selectors"
+	"github.com/go-rod/rod"
+)
+
+// warningSignals pairs each account warning selector with the breaker signal it trips
+var warningSignals = []struct {
+	key    selectors.Key
+	signal guard.Signal
+}{
+	{selectors.WarningCheckpoint, guard.SignalCheckpoint},
+	{selectors.WarningRestricted, guard.SignalRestricted},
+	{selectors.WarningInviteLimit, guard.SignalInviteLimit},
+}
+
+// checkWarnings trips the circuit breaker if the current page is a security checkpoint or shows
+// a restricted-account notice or the weekly invitation limit. The error (nil for a clean page)
+// wraps guard.ErrStopped and guard.ErrTripped.
+func checkWarnings(page *rod.Page, sel *selectors.Registry) error {
+	url := page.MustInfo().URL
+	if strings.Contains(url, "/checkpoint/") {
+		return guard.Trip(guard.SignalCheckpoint, "redirected to "+url)
+	}
+	for _, w := range warningSignals {
+		if el, err := find(page, sel, w.key, 0); err == nil {
+			return guard.Trip(w.signal, fmt.Sprintf("%q on %s", cleanText(el), url))
+		}
+	}
+	return nil
+}
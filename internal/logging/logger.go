@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Logger is a package's logger. Packages declare one as a package-level variable and expose its
+// Set as their SetLogger; until it is called, records go to slog's default logger at the time
+// they are written. The zero value is ready to use and safe for concurrent use.
+type Logger struct {
+	l atomic.Pointer[slog.Logger]
+}
+
+// Set makes l receive every later record; nil restores slog's default logger.
+func (p *Logger) Set(l *slog.Logger) {
+	p.l.Store(l)
+}
+
+// Logger returns the logger records currently go to.
+func (p *Logger) Logger() *slog.Logger {
+	if l := p.l.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// With returns a logger that adds args to every record, as slog.Logger.With.
+func (p *Logger) With(args ...any) *slog.Logger {
+	return p.Logger().With(args...)
+}
+
+// Debug logs at debug level.
+func (p *Logger) Debug(msg string, args ...any) {
+	p.Logger().Debug(msg, args...)
+}
+
+// Info logs at info level.
+func (p *Logger) Info(msg string, args ...any) {
+	p.Logger().Info(msg, args...)
+}
+
+// Warn logs at warn level.
+func (p *Logger) Warn(msg string, args ...any) {
+	p.Logger().Warn(msg, args...)
+}
+
+// Error logs at error level.
+func (p *Logger) Error(msg string, args ...any) {
+	p.Logger().Error(msg, args...)
+}
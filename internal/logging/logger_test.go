@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var def, set bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&def, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	var logger Logger
+	logger.Info("before Set")
+	logger.Set(slog.New(slog.NewTextHandler(&set, nil)).With(KeyRunID, "run-1"))
+	logger.With(Profile("https://example.com/in/jane/")).Warn("after Set")
+	logger.Set(nil)
+	logger.Error("after reset")
+
+	if got := def.String(); !strings.Contains(got, "before Set") || !strings.Contains(got, "after reset") || strings.Contains(got, "after Set") {
+		t.Errorf("default logger got %q, want the records before Set and after the reset", got)
+	}
+	if got := set.String(); !strings.Contains(got, "after Set") || !strings.Contains(got, "run_id=run-1") || !strings.Contains(got, "profile_url=") {
+		t.Errorf("set logger got %q, want the tagged record written after Set", got)
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Attribute keys every package uses, so records can be correlated across a run.
+const (
+	KeyRunID    = "run_id"
+	KeyMode     = "mode"
+	KeyCampaign = "campaign"
+	KeyProfile  = "profile_url"
+	KeyError    = "error"
+)
+
+// Output formats
+const (
+	FormatText = "text" // key=value lines for a terminal
+	FormatJSON = "json" // One JSON object per line for log pipelines
+)
+
+// New returns a logger writing records at level or above to w in format ("text" or "json").
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (use %q or %q)", format, FormatText, FormatJSON)
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error".
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", level)
+	}
+	return lvl, nil
+}
+
+// Profile tags a record with the profile it is about.
+func Profile(url string) slog.Attr {
+	return slog.String(KeyProfile, url)
+}
+
+// Err tags a record with an error.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
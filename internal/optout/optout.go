@@ -0,0 +1,113 @@
+package optout
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//go:embed default.json
+var defaultFile []byte
+
+// file is the on-disk JSON layout of an opt-out rules file.
+type file struct {
+	Keywords []string `json:"keywords"`
+	Patterns []string `json:"patterns"`
+}
+
+// Classifier decides whether a reply asks us to stop contacting someone.
+// Keywords match as case-insensitive substrings, patterns as case-insensitive regular expressions.
+type Classifier struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// Default returns the classifier built from the embedded rules.
+func Default() *Classifier {
+	c, err := Parse(defaultFile)
+	if err != nil {
+		panic(fmt.Sprintf("optout: embedded default.json is invalid: %v", err))
+	}
+	return c
+}
+
+// Load reads and validates a rules file. An empty path returns the embedded default.
+func Load(path string) (*Classifier, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read opt-out rules file: %w", err)
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opt-out rules file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes and validates rules file contents, collecting every problem.
+func Parse(data []byte) (*Classifier, error) {
+	// Unknown keys are rejected so a misspelled field is not silently ignored
+	var f file
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+
+	c := &Classifier{}
+	var problems []string
+	for i, kw := range f.Keywords {
+		kw = normalize(kw)
+		if kw == "" {
+			problems = append(problems, fmt.Sprintf("keywords[%d]: empty keyword", i))
+			continue
+		}
+		c.keywords = append(c.keywords, kw)
+	}
+	for i, p := range f.Patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("patterns[%d]: %v", i, err))
+			continue
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	if len(f.Keywords)+len(f.Patterns) == 0 {
+		problems = append(problems, "at least one keyword or pattern is required")
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return c, nil
+}
+
+// Match reports whether text is an opt-out request and the rule that matched it.
+func (c *Classifier) Match(text string) (string, bool) {
+	text = normalize(text)
+	for _, kw := range c.keywords {
+		if strings.Contains(text, kw) {
+			return kw, true
+		}
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(text) {
+			return strings.TrimPrefix(re.String(), "(?i)"), true
+		}
+	}
+	return "", false
+}
+
+// normalize lowercases text, straightens curly apostrophes and collapses whitespace
+func normalize(text string) string {
+	text = strings.NewReplacer("’", "'", "‘", "'").Replace(text)
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,78 @@
+package optout
+
+import "testing"
+
+func TestDefaultMatch(t *testing.T) {
+	c := Default()
+
+	tests := []struct {
+		reply string
+		want  bool
+	}{
+		{"Please stop messaging me.", true},
+		{"STOP CONTACTING ME", true},
+		{"Don’t message me again", true},
+		{"dont contact me", true},
+		{"Please remove me from your list", true},
+		{"I'd like to opt out", true},
+		{"Opted-out, thanks", true},
+		{"unsubscribe", true},
+		{"Not interested,\n\nplease   stop", true},
+		{"Thanks,\nbut please stop\nmessaging me", true},
+		{"Thanks! Happy to chat next week.", false},
+		{"I can't stop thinking about your product", false},
+		{"Sure, message me on Monday", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		rule, got := c.Match(tt.reply)
+		if got != tt.want {
+			t.Errorf("Match(%q) = %v (rule %q), want %v", tt.reply, got, rule, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"keywords only", `{"keywords": ["go away"]}`, false},
+		{"patterns only", `{"patterns": ["\\bno thanks\\b"]}`, false},
+		{"no rules", `{}`, true},
+		{"empty keyword", `{"keywords": ["  "]}`, true},
+		{"invalid pattern", `{"patterns": ["(unclosed"]}`, true},
+		{"not JSON", `keywords: [x]`, true},
+		{"unknown field", `{"keywords": ["go away"], "keyword": ["stop"]}`, true},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Parse error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	c, err := Parse([]byte(`{"keywords": ["Go Away"], "patterns": ["\\bno\\s+thanks\\b"]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		reply    string
+		wantRule string
+		want     bool
+	}{
+		{"please GO   away", "go away", true},
+		{"No thanks!", `\bno\s+thanks\b`, true},
+		{"Please stop messaging me", "", false},
+	}
+	for _, tt := range tests {
+		rule, got := c.Match(tt.reply)
+		if got != tt.want || rule != tt.wantRule {
+			t.Errorf("Match(%q) = (%q, %v), want (%q, %v)", tt.reply, rule, got, tt.wantRule, tt.want)
+		}
+	}
+}
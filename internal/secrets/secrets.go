@@ -0,0 +1,214 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Key derivation parameters for new files. Existing files keep the iterations they were written with.
+const (
+	version    = 1
+	kdfName    = "pbkdf2-sha256"
+	iterations = 600000
+	saltSize   = 16
+	keySize    = 32 // AES-256
+)
+
+// ErrWrongPassphrase is returned when a secrets file cannot be decrypted with the given passphrase.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted secrets file")
+
+// envelope is the on-disk JSON layout: key derivation parameters and the AES-GCM sealed vault.
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// vault is the decrypted contents of a secrets file.
+type vault struct {
+	Password string          `json:"password,omitempty"`
+	Cookies  json.RawMessage `json:"cookies,omitempty"` // Browser cookie jar as saved by the login flow
+}
+
+// Store is a passphrase-encrypted file holding the LinkedIn password and session cookies.
+// Every change is written straight back to disk with 0600 permissions.
+type Store struct {
+	path       string
+	key        []byte
+	salt       []byte
+	iterations int
+	vault      vault
+}
+
+// Open decrypts the secrets file at path. A missing file gives an empty store that is created on first save.
+func Open(path, passphrase string) (*Store, error) {
+	if passphrase == "" {
+		return nil, errors.New("secrets passphrase is empty")
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		s := &Store{path: path, salt: salt, iterations: iterations}
+		if s.key, err = deriveKey(passphrase, salt, iterations); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", path, err)
+	}
+	if env.Version != version || env.KDF != kdfName {
+		return nil, fmt.Errorf("secrets file %s: unsupported format (version %d, kdf %q)", path, env.Version, env.KDF)
+	}
+	if len(env.Salt) == 0 || env.Iterations < 1 {
+		return nil, fmt.Errorf("secrets file %s: missing key derivation parameters", path)
+	}
+
+	s := &Store{path: path, salt: env.Salt, iterations: env.Iterations}
+	if s.key, err = deriveKey(passphrase, env.Salt, env.Iterations); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("secrets file %s: %w", path, ErrWrongPassphrase)
+	}
+	plain, err := gcm.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("secrets file %s: %w", path, ErrWrongPassphrase)
+	}
+	if err := json.Unmarshal(plain, &s.vault); err != nil {
+		return nil, fmt.Errorf("secrets file %s: invalid contents: %w", path, err)
+	}
+	return s, nil
+}
+
+// Passphrase returns the passphrase from keyFile (its contents, surrounding whitespace trimmed)
+// or, when keyFile is empty, the passphrase itself.
+func Passphrase(passphrase, keyFile string) (string, error) {
+	if keyFile == "" {
+		if passphrase == "" {
+			return "", errors.New("set SECRETS_PASSPHRASE or SECRETS_KEY_FILE to unlock the secrets file")
+		}
+		return passphrase, nil
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets key file: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("secrets key file %s is empty", keyFile)
+	}
+	return key, nil
+}
+
+// Path returns the file the store reads and writes.
+func (s *Store) Path() string { return s.path }
+
+// Password returns the stored LinkedIn password, "" if none.
+func (s *Store) Password() string { return s.vault.Password }
+
+// SetPassword stores the LinkedIn password and saves the file.
+func (s *Store) SetPassword(password string) error {
+	s.vault.Password = password
+	return s.save()
+}
+
+// Cookies returns the stored cookie jar JSON, nil if none.
+func (s *Store) Cookies() []byte { return s.vault.Cookies }
+
+// SetCookies stores the cookie jar JSON and saves the file.
+func (s *Store) SetCookies(data []byte) error {
+	if !json.Valid(data) {
+		return errors.New("cookies are not valid JSON")
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+	s.vault.Cookies = compact.Bytes()
+	return s.save()
+}
+
+// save encrypts the vault with a fresh nonce and atomically replaces the file, readable by the owner only
+func (s *Store) save() error {
+	plain, err := json.Marshal(s.vault)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(envelope{
+		Version:    version,
+		KDF:        kdfName,
+		Iterations: s.iterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// deriveKey stretches the passphrase into an AES-256 key
+func deriveKey(passphrase string, salt []byte, iter int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, iter, keySize)
+}
+
+// newGCM returns the AES-GCM AEAD for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,184 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testIterations keeps key derivation cheap; Open reads the count back from the file
+const testIterations = 1000
+
+// newTestStore opens a store for a new file in a temporary directory, using testIterations
+func newTestStore(t *testing.T, passphrase string) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "secrets.json"), passphrase)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	s.iterations = testIterations
+	if s.key, err = deriveKey(passphrase, s.salt, s.iterations); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// readEnvelope returns the on-disk layout of the file at path
+func readEnvelope(t *testing.T, path string) envelope {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	return env
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := newTestStore(t, "correct horse")
+	if err := s.SetPassword("hunter2"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	if err := s.SetCookies([]byte(`[ {"name": "li_at", "value": "abc"} ]`)); err != nil {
+		t.Fatalf("SetCookies: %v", err)
+	}
+
+	data, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) || bytes.Contains(data, []byte("li_at")) {
+		t.Error("file holds the password or cookies in plain text")
+	}
+
+	reopened, err := Open(s.Path(), "correct horse")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := reopened.Password(); got != "hunter2" {
+		t.Errorf("Password = %q, want hunter2", got)
+	}
+	if got, want := string(reopened.Cookies()), `[{"name":"li_at","value":"abc"}]`; got != want {
+		t.Errorf("Cookies = %s, want %s", got, want)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(env *envelope)
+		passphrase string
+	}{
+		{"wrong passphrase", func(env *envelope) {}, "wrong horse"},
+		{"tampered ciphertext", func(env *envelope) { env.Data[0] ^= 0xff }, "correct horse"},
+		{"tampered nonce", func(env *envelope) { env.Nonce[0] ^= 0xff }, "correct horse"},
+		{"truncated nonce", func(env *envelope) { env.Nonce = env.Nonce[:4] }, "correct horse"},
+	}
+	for _, tt := range tests {
+		s := newTestStore(t, "correct horse")
+		if err := s.SetPassword("hunter2"); err != nil {
+			t.Fatalf("SetPassword: %v", err)
+		}
+		env := readEnvelope(t, s.Path())
+		tt.tamper(&env)
+		data, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(s.Path(), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Open(s.Path(), tt.passphrase); !errors.Is(err, ErrWrongPassphrase) {
+			t.Errorf("%s: Open error = %v, want ErrWrongPassphrase", tt.name, err)
+		}
+	}
+}
+
+func TestSaveRestrictsPermissions(t *testing.T) {
+	s := newTestStore(t, "correct horse")
+	if err := s.SetPassword("hunter2"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	info, err := os.Stat(s.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("secrets file has mode %o, want 600", perm)
+	}
+}
+
+func TestKeyDerivationPersists(t *testing.T) {
+	s := newTestStore(t, "correct horse")
+	if err := s.SetPassword("hunter2"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	first := readEnvelope(t, s.Path())
+	if !bytes.Equal(first.Salt, s.salt) || first.Iterations != testIterations {
+		t.Fatalf("saved salt %x with %d iterations, want %x with %d", first.Salt, first.Iterations, s.salt, testIterations)
+	}
+
+	// The file keeps its salt and iteration count through a reopen and another save
+	reopened, err := Open(s.Path(), "correct horse")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if !bytes.Equal(reopened.salt, first.Salt) || reopened.iterations != testIterations {
+		t.Errorf("reopened with salt %x and %d iterations, want %x and %d", reopened.salt, reopened.iterations, first.Salt, testIterations)
+	}
+	if err := reopened.SetCookies([]byte("[]")); err != nil {
+		t.Fatalf("SetCookies: %v", err)
+	}
+	second := readEnvelope(t, s.Path())
+	if !bytes.Equal(second.Salt, first.Salt) || second.Iterations != testIterations {
+		t.Errorf("resaved salt %x with %d iterations, want %x with %d", second.Salt, second.Iterations, first.Salt, testIterations)
+	}
+	if bytes.Equal(second.Nonce, first.Nonce) {
+		t.Error("save reused the nonce")
+	}
+}
+
+func TestOpenEmptyPassphrase(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "secrets.json"), ""); err == nil {
+		t.Error("Open with an empty passphrase: no error")
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("  from file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	blankFile := filepath.Join(dir, "blank")
+	if err := os.WriteFile(blankFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		keyFile    string
+		want       string
+		wantErr    bool
+	}{
+		{"passphrase", "direct", "", "direct", false},
+		{"key file wins", "direct", keyFile, "from file", false},
+		{"neither", "", "", "", true},
+		{"blank key file", "", blankFile, "", true},
+		{"missing key file", "", filepath.Join(dir, "missing"), "", true},
+	}
+	for _, tt := range tests {
+		got, err := Passphrase(tt.passphrase, tt.keyFile)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: Passphrase = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package selectors
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Key identifies a UI element the bot interacts with.
+type Key string
+
+// Every selector key used by internal/linkedin. A selector file must define all of them.
+const (
+	LoginUsername Key = "login.username"
+	LoginPassword Key = "login.password"
+	LoginSubmit   Key = "login.submit"
+	GlobalNav     Key = "nav.global"
+
+	SearchInput        Key = "search.input"
+	SearchPeopleFilter Key = "search.people_filter"
+	SearchModalDismiss Key = "search.modal_dismiss"
+	SearchResultLink   Key = "search.result_link"
+	SearchNext         Key = "search.next"
+	SearchResultCard   Key = "search.result_card"
+	SearchCardName     Key = "search.card_name"
+	SearchCardHeadline Key = "search.card_headline"
+	SearchCardLocation Key = "search.card_location"
+
+	ProfileName        Key = "profile.name"
+	ProfileHeadline    Key = "profile.headline"
+	ProfileCompany     Key = "profile.company"
+	ProfileLocation    Key = "profile.location"
+	ProfilePending     Key = "profile.pending"
+	ProfileMessage     Key = "profile.message"
+	ProfileMessageLock Key = "profile.message_lock"
+	ProfileInMail      Key = "profile.inmail"
+
+	ConnectButton    Key = "connect.button"
+	ConnectMore      Key = "connect.more"
+	ConnectMenuItem  Key = "connect.menu_item"
+	ConnectAddNote   Key = "connect.add_note"
+	ConnectNoteInput Key = "connect.note_input"
+	ConnectSend      Key = "connect.send"
+
+	MessageChatInput    Key = "message.chat_input"
+	MessageSend         Key = "message.send"
+	MessageChatClose    Key = "message.chat_close"
+	MessagePremiumPopup Key = "message.premium_popup"
+	MessagePopupClose   Key = "message.popup_close"
+	MessageIncoming     Key = "message.incoming"
+
+	InboxThreadLink    Key = "inbox.thread_link"
+	InboxThreadProfile Key = "inbox.thread_profile"
+	InboxMessage       Key = "inbox.message"
+	InboxMessageBody   Key = "inbox.message_body"
+	InboxMessageOther  Key = "inbox.message_other"
+	InboxMessageSender Key = "inbox.message_sender"
+	InboxMessageTime   Key = "inbox.message_time"
+
+	// Account warnings: any match trips the circuit breaker
+	WarningInviteLimit Key = "warning.invite_limit"
+	WarningCheckpoint  Key = "warning.checkpoint"
+	WarningRestricted  Key = "warning.restricted"
+)
+
+var knownKeys = []Key{
+	LoginUsername, LoginPassword, LoginSubmit, GlobalNav,
+	SearchInput, SearchPeopleFilter, SearchModalDismiss, SearchResultLink, SearchNext,
+	SearchResultCard, SearchCardName, SearchCardHeadline, SearchCardLocation,
+	ProfileName, ProfileHeadline, ProfileCompany, ProfileLocation,
+	ProfilePending, ProfileMessage, ProfileMessageLock, ProfileInMail,
+	ConnectButton, ConnectMore, ConnectMenuItem, ConnectAddNote, ConnectNoteInput, ConnectSend,
+	MessageChatInput, MessageSend, MessageChatClose, MessagePremiumPopup, MessagePopupClose, MessageIncoming,
+	InboxThreadLink, InboxThreadProfile, InboxMessage, InboxMessageBody, InboxMessageOther,
+	InboxMessageSender, InboxMessageTime,
+	WarningInviteLimit, WarningCheckpoint, WarningRestricted,
+}
+
+//go:embed default.json
+var defaultFile []byte
+
+// Candidate is one way of locating an element: a CSS selector, optionally
+// narrowed by a regular expression on the element's text.
+type Candidate struct {
+	CSS  string `json:"css"`
+	Text string `json:"text,omitempty"`
+}
+
+// file is the on-disk JSON layout of a selector file.
+type file struct {
+	Name      string              `json:"name"`
+	Version   int                 `json:"version"`
+	Selectors map[Key][]Candidate `json:"selectors"`
+}
+
+// Registry holds a validated selector set. Candidates for a key are tried in order,
+// so later entries act as fallbacks when the markup changes.
+type Registry struct {
+	name    string
+	version int
+	entries map[Key][]Candidate
+}
+
+// Keys returns every key a selector file must define, sorted.
+func Keys() []Key {
+	keys := append([]Key(nil), knownKeys...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Default returns the registry embedded in the binary.
+func Default() *Registry {
+	reg, err := Parse(defaultFile)
+	if err != nil {
+		panic(fmt.Sprintf("selectors: embedded default.json is invalid: %v", err))
+	}
+	return reg
+}
+
+// Load reads and validates a selector file. An empty path returns the embedded default.
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selector file: %w", err)
+	}
+	reg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector file %s: %w", path, err)
+	}
+	return reg, nil
+}
+
+// Parse decodes and validates selector file contents.
+func Parse(data []byte) (*Registry, error) {
+	// Unknown keys are rejected so a misspelled field is not silently ignored
+	var f file
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	if err := validate(&f); err != nil {
+		return nil, err
+	}
+	return &Registry{name: f.Name, version: f.Version, entries: f.Selectors}, nil
+}
+
+// validate collects every problem in the file so a broken file can be fixed in one pass
+func validate(f *file) error {
+	var problems []string
+	if strings.TrimSpace(f.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if f.Version < 1 {
+		problems = append(problems, "version must be >= 1")
+	}
+
+	known := make(map[Key]bool, len(knownKeys))
+	for _, k := range knownKeys {
+		known[k] = true
+		if len(f.Selectors[k]) == 0 {
+			problems = append(problems, fmt.Sprintf("%s: missing", k))
+		}
+	}
+
+	for key, candidates := range f.Selectors {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("%s: unknown key", key))
+			continue
+		}
+		for i, c := range candidates {
+			if strings.TrimSpace(c.CSS) == "" {
+				problems = append(problems, fmt.Sprintf("%s[%d]: css is required", key, i))
+			}
+			if c.Text != "" {
+				if _, err := regexp.Compile(c.Text); err != nil {
+					problems = append(problems, fmt.Sprintf("%s[%d]: invalid text regex: %v", key, i, err))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Name returns the selector set's name.
+func (r *Registry) Name() string { return r.name }
+
+// Version returns the selector set's version.
+func (r *Registry) Version() int { return r.version }
+
+// Get returns the candidates for key in priority order.
+func (r *Registry) Get(key Key) []Candidate {
+	return r.entries[key]
+}
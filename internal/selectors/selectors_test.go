@@ -0,0 +1,149 @@
+package selectors
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// defaultLayout returns a fresh copy of the embedded selector file for a test to change
+func defaultLayout(t *testing.T) file {
+	t.Helper()
+	var f file
+	if err := json.Unmarshal(defaultFile, &f); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// marshal encodes f as a selector file
+func marshal(t *testing.T, f file) []byte {
+	t.Helper()
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDefault(t *testing.T) {
+	reg := Default()
+	if reg.Name() == "" || reg.Version() < 1 {
+		t.Errorf("default registry is %q v%d, want a name and a version", reg.Name(), reg.Version())
+	}
+	for _, k := range Keys() {
+		if len(reg.Get(k)) == 0 {
+			t.Errorf("%s: no candidates in the default registry", k)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(f *file)
+		want   string // Substring of the error
+	}{
+		{"no name", func(f *file) { f.Name = " " }, "name is required"},
+		{"no version", func(f *file) { f.Version = 0 }, "version must be >= 1"},
+		{"missing key", func(f *file) { delete(f.Selectors, ConnectSend) }, "connect.send: missing"},
+		{"empty candidate list", func(f *file) { f.Selectors[ConnectSend] = nil }, "connect.send: missing"},
+		{"unknown key", func(f *file) { f.Selectors["connect.sned"] = []Candidate{{CSS: "button"}} }, "connect.sned: unknown key"},
+		{"empty css", func(f *file) { f.Selectors[ConnectMore] = []Candidate{{CSS: "button"}, {CSS: " "}} }, "connect.more[1]: css is required"},
+		{"invalid text regex", func(f *file) { f.Selectors[ConnectMore] = []Candidate{{CSS: "button", Text: "(More"}} }, "connect.more[0]: invalid text regex"},
+	}
+	for _, tt := range tests {
+		f := defaultLayout(t)
+		tt.change(&f)
+		if _, err := Parse(marshal(t, f)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Parse error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseReportsEveryProblem(t *testing.T) {
+	f := defaultLayout(t)
+	f.Version = 0
+	delete(f.Selectors, LoginSubmit)
+	f.Selectors[SearchNext] = []Candidate{{CSS: ""}}
+
+	_, err := Parse(marshal(t, f))
+	if err == nil {
+		t.Fatal("Parse: no error")
+	}
+	for _, want := range []string{"version must be >= 1", "login.submit: missing", "search.next[0]: css is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestParseUnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+	}{
+		{"top level", `"version": 5,`, `"version": 5, "verison": 6,`},
+		{"candidate", `{ "css": "#username" }`, `{ "css": "#username", "txt": "Email" }`},
+	}
+	for _, tt := range tests {
+		data := strings.Replace(string(defaultFile), tt.old, tt.new, 1)
+		if data == string(defaultFile) {
+			t.Fatalf("%s: %q is not in default.json", tt.name, tt.old)
+		}
+		if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("%s: Parse error = %v, want an unknown field", tt.name, err)
+		}
+	}
+}
+
+func TestLoadOverride(t *testing.T) {
+	if reg, err := Load(""); err != nil || reg.Name() != Default().Name() {
+		t.Fatalf("Load(\"\") = %v, %v; want the default registry", reg, err)
+	}
+
+	// A copy of the default with one key changed, as the README suggests
+	f := defaultLayout(t)
+	f.Name, f.Version = "patched", 6
+	f.Selectors[ConnectButton] = []Candidate{{CSS: "button.connect-v2"}, {CSS: "button", Text: "^Connect$"}}
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	if err := os.WriteFile(path, marshal(t, f), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if reg.Name() != "patched" || reg.Version() != 6 {
+		t.Errorf("loaded %q v%d, want patched v6", reg.Name(), reg.Version())
+	}
+	if got := reg.Get(ConnectButton); !slices.Equal(got, f.Selectors[ConnectButton]) {
+		t.Errorf("connect.button = %+v, want the file's candidates in order %+v", got, f.Selectors[ConnectButton])
+	}
+	def := Default()
+	for _, k := range Keys() {
+		if k != ConnectButton && !slices.Equal(reg.Get(k), def.Get(k)) {
+			t.Errorf("%s = %+v, want the unchanged %+v", k, reg.Get(k), def.Get(k))
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"name": "broken", "version": 1, "selectors": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Load of a missing file: no error")
+	}
+	if _, err := Load(invalid); err == nil || !strings.Contains(err.Error(), invalid) {
+		t.Errorf("Load error = %v, want it to name %s", err, invalid)
+	}
+}
@@ -0,0 +1,206 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/secrets"
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// LegacyFile is where older versions dumped the cookie jar. It is imported once and deleted, unless
+// a session is already saved.
+const LegacyFile = "cookies.json"
+
+// AuthCookies keep a LinkedIn login alive: the session is only as valid as the first of them to expire.
+var AuthCookies = []string{"li_at", "JSESSIONID"}
+
+// ErrNoSession is returned by Load when the account has no saved cookies.
+var ErrNoSession = errors.New("no saved session")
+
+// Save records the browser's cookies as the account's session, with the time they were captured and
+// when the first auth cookie expires. The cookies themselves go to the secrets store if there is one,
+// otherwise into the database.
+func Save(db *sql.DB, store *secrets.Store, account string, cookies []*proto.NetworkCookie) (*storage.Session, error) {
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return nil, err
+	}
+	s := describe(account, cookies, time.Now())
+	if err := keep(store, s, data); err != nil {
+		return nil, err
+	}
+	return s, storage.SaveSession(db, s)
+}
+
+// Load returns the account's saved cookies and their session record, or ErrNoSession.
+// Cookies left in cookies.json, or in the database after a secrets file was set up, are migrated first.
+func Load(db *sql.DB, store *secrets.Store, account string) ([]*proto.NetworkCookie, *storage.Session, error) {
+	if err := Migrate(db, store, account); err != nil {
+		return nil, nil, err
+	}
+	s, err := storage.GetSession(db, account)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := s.Cookies
+	if store != nil {
+		data = store.Cookies()
+	} else if data == nil && s.CookieCount > 0 {
+		return nil, s, errors.New("the saved session is in the secrets file: set SECRETS_FILE to use it")
+	}
+	if data == nil {
+		return nil, s, ErrNoSession
+	}
+
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, s, fmt.Errorf("invalid saved cookies: %w", err)
+	}
+	return cookies, s, nil
+}
+
+// Get returns the account's session record without touching the cookies, or ErrNoSession.
+func Get(db *sql.DB, account string) (*storage.Session, error) {
+	s, err := storage.GetSession(db, account)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSession
+	}
+	return s, err
+}
+
+// Migrate moves cookies to where Save puts them now: a cookies.json from older versions into the
+// session store (then deletes it), and cookies from the database into the secrets file once one is
+// configured. Cookies that only exist in the secrets file get a session record. A cookies.json is
+// only deleted once imported: next to an existing session it is ignored and left in place.
+func Migrate(db *sql.DB, store *secrets.Store, account string) error {
+	existing, err := storage.GetSession(db, account)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if info, err := os.Stat(LegacyFile); err == nil {
+		if existing != nil || (store != nil && store.Cookies() != nil) {
+			logger.Warn("🍪 Ignored legacy cookie file: a session is already saved", "file", LegacyFile, "account", account)
+		} else {
+			data, err := os.ReadFile(LegacyFile)
+			if err != nil {
+				return err
+			}
+			if existing, err = adopt(db, store, account, data, info.ModTime()); err != nil {
+				return fmt.Errorf("failed to import %s: %w", LegacyFile, err)
+			}
+			if err := os.Remove(LegacyFile); err != nil {
+				return err
+			}
+			logger.Info("🍪 Moved legacy cookie file into the session store", "file", LegacyFile, "account", account)
+		}
+	}
+
+	if store == nil {
+		return nil
+	}
+	switch {
+	case existing != nil && existing.Cookies != nil:
+		if err := store.SetCookies(existing.Cookies); err != nil {
+			return err
+		}
+		existing.Cookies = nil
+		if err := storage.SaveSession(db, existing); err != nil {
+			return err
+		}
+		logger.Info("🔐 Moved the saved session into the encrypted secrets file", "file", store.Path(), "account", account)
+	case existing == nil && store.Cookies() != nil:
+		if _, err := adopt(db, store, account, store.Cookies(), time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adopt saves a cookie jar found outside the session store, as if it had been captured at capturedAt
+func adopt(db *sql.DB, store *secrets.Store, account string, data []byte, capturedAt time.Time) (*storage.Session, error) {
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, err
+	}
+	s := describe(account, cookies, capturedAt)
+	if err := keep(store, s, data); err != nil {
+		return nil, err
+	}
+	return s, storage.SaveSession(db, s)
+}
+
+// keep puts the cookie jar in the secrets store, or on s for the database when there is no store
+func keep(store *secrets.Store, s *storage.Session, data []byte) error {
+	if store != nil {
+		return store.SetCookies(data)
+	}
+	s.Cookies = data
+	return nil
+}
+
+// describe builds the session record for cookies, finding the auth cookie that expires first
+func describe(account string, cookies []*proto.NetworkCookie, capturedAt time.Time) *storage.Session {
+	s := &storage.Session{Account: account, CookieCount: len(cookies), CapturedAt: capturedAt}
+	for _, c := range cookies {
+		if !slices.Contains(AuthCookies, c.Name) || c.Expires <= 0 { // <= 0: browser-session cookie
+			continue
+		}
+		at := c.Expires.Time()
+		if !s.ExpiresAt.Valid || at.Before(s.ExpiresAt.Time) {
+			s.ExpiresAt = sql.NullTime{Time: at, Valid: true}
+			s.AuthCookie = c.Name
+		}
+	}
+	return s
+}
+
+// Remaining returns how long the session stays valid after now (negative once expired).
+// ok is false when no auth cookie has an expiry, so the remaining time is unknown.
+func Remaining(s *storage.Session, now time.Time) (remaining time.Duration, ok bool) {
+	if !s.ExpiresAt.Valid {
+		return 0, false
+	}
+	return s.ExpiresAt.Time.Sub(now), true
+}
+
+// ExpiryWarning returns a warning if the session is expired or expires within window, "" otherwise.
+func ExpiryWarning(s *storage.Session, window time.Duration, now time.Time) string {
+	remaining, ok := Remaining(s, now)
+	switch {
+	case !ok:
+		return ""
+	case remaining <= 0:
+		return fmt.Sprintf("the saved session expired on %s: the next run will log in with the password", s.ExpiresAt.Time.Local().Format("2006-01-02 15:04"))
+	case remaining <= window:
+		return fmt.Sprintf("the saved session expires in %s (%s, cookie %s): log in again before then", FormatDuration(remaining), s.ExpiresAt.Time.Local().Format("2006-01-02 15:04"), s.AuthCookie)
+	}
+	return ""
+}
+
+// FormatDuration renders d as days and hours, e.g. "3d 4h" or "45m"
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
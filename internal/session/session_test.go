@@ -0,0 +1,208 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/storage"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const account = "test@example.com"
+
+// openTestDB creates a fresh database in a temporary working directory, where Migrate looks for
+// the legacy cookie file
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// cookie returns a cookie that expires at expires; the zero time makes a browser-session cookie
+func cookie(name string, expires time.Time) *proto.NetworkCookie {
+	c := &proto.NetworkCookie{Name: name, Value: "x"}
+	if !expires.IsZero() {
+		c.Expires = proto.TimeSinceEpoch(expires.Unix())
+	}
+	return c
+}
+
+// writeLegacy writes cookies.json as older versions did
+func writeLegacy(t *testing.T, cookies []*proto.NetworkCookie) {
+	t.Helper()
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(LegacyFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// legacyExists reports whether cookies.json is still there
+func legacyExists(t *testing.T) bool {
+	t.Helper()
+	_, err := os.Stat(LegacyFile)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestMigrateImportsLegacyFile(t *testing.T) {
+	db := openTestDB(t)
+	expires := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	writeLegacy(t, []*proto.NetworkCookie{cookie("li_at", expires), cookie("bcookie", time.Time{})})
+
+	if err := Migrate(db, nil, account); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if legacyExists(t) {
+		t.Error("cookies.json is left after import")
+	}
+	s, err := Get(db, account)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if s.CookieCount != 2 || s.AuthCookie != "li_at" || !s.ExpiresAt.Time.Equal(expires) {
+		t.Errorf("imported session = %d cookies, %s expiring %v; want 2, li_at expiring %s", s.CookieCount, s.AuthCookie, s.ExpiresAt, expires)
+	}
+	if s.Cookies == nil {
+		t.Error("imported cookies are not in the database")
+	}
+}
+
+func TestMigrateKeepsLegacyFileBesideSession(t *testing.T) {
+	db := openTestDB(t)
+	saved, err := Save(db, nil, account, []*proto.NetworkCookie{cookie("li_at", time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	writeLegacy(t, []*proto.NetworkCookie{cookie("li_at", time.Now().Add(72*time.Hour)), cookie("JSESSIONID", time.Time{})})
+
+	if err := Migrate(db, nil, account); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if !legacyExists(t) {
+		t.Error("cookies.json was deleted without being imported")
+	}
+	s, err := Get(db, account)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if s.CookieCount != saved.CookieCount || !s.ExpiresAt.Time.Equal(saved.ExpiresAt.Time) {
+		t.Errorf("session = %d cookies expiring %v, want the saved %d expiring %v", s.CookieCount, s.ExpiresAt, saved.CookieCount, saved.ExpiresAt)
+	}
+}
+
+func TestMigrateKeepsUnreadableLegacyFile(t *testing.T) {
+	db := openTestDB(t)
+	if err := os.WriteFile(LegacyFile, []byte("not JSON"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(db, nil, account); err == nil {
+		t.Error("Migrate of an invalid cookies.json: no error")
+	}
+	if !legacyExists(t) {
+		t.Error("cookies.json was deleted although the import failed")
+	}
+	if _, err := Get(db, account); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Get = %v, want ErrNoSession", err)
+	}
+}
+
+func TestDescribeEarliestExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		cookies    []*proto.NetworkCookie
+		wantCookie string
+		want       time.Time // Zero when no auth cookie has an expiry
+	}{
+		{
+			name: "first auth cookie to expire",
+			cookies: []*proto.NetworkCookie{
+				cookie("li_at", now.Add(240*time.Hour)),
+				cookie("JSESSIONID", now.Add(72*time.Hour)),
+				cookie("bcookie", now.Add(time.Hour)), // Not an auth cookie
+			},
+			wantCookie: "JSESSIONID",
+			want:       now.Add(72 * time.Hour),
+		},
+		{
+			name:       "browser-session auth cookie has no expiry",
+			cookies:    []*proto.NetworkCookie{cookie("li_at", time.Time{}), cookie("JSESSIONID", now.Add(time.Hour))},
+			wantCookie: "JSESSIONID",
+			want:       now.Add(time.Hour),
+		},
+		{
+			name:    "no auth cookie",
+			cookies: []*proto.NetworkCookie{cookie("bcookie", now.Add(time.Hour))},
+		},
+	}
+	for _, tt := range tests {
+		s := describe(account, tt.cookies, now)
+		if s.CookieCount != len(tt.cookies) || !s.CapturedAt.Equal(now) {
+			t.Errorf("%s: %d cookies captured at %s, want %d at %s", tt.name, s.CookieCount, s.CapturedAt, len(tt.cookies), now)
+		}
+		if s.ExpiresAt.Valid != !tt.want.IsZero() || !s.ExpiresAt.Time.Equal(tt.want) || s.AuthCookie != tt.wantCookie {
+			t.Errorf("%s: expires %v (%q), want %s (%q)", tt.name, s.ExpiresAt, s.AuthCookie, tt.want, tt.wantCookie)
+		}
+	}
+}
+
+func TestExpiryWarning(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	window := 72 * time.Hour
+	expiring := func(d time.Duration) *storage.Session {
+		return &storage.Session{AuthCookie: "li_at", ExpiresAt: sql.NullTime{Time: now.Add(d), Valid: true}}
+	}
+
+	tests := []struct {
+		name    string
+		session *storage.Session
+		want    string // Substring of the warning, "" for none
+	}{
+		{"unknown expiry", &storage.Session{}, ""},
+		{"already expired", expiring(-time.Minute), "expired on"},
+		{"expires right now", expiring(0), "expired on"},
+		{"inside the window", expiring(26 * time.Hour), "expires in 1d 2h"},
+		{"at the window's edge", expiring(window), "expires in 3d 0h"},
+		{"beyond the window", expiring(window + time.Minute), ""},
+	}
+	for _, tt := range tests {
+		got := ExpiryWarning(tt.session, window, now)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: ExpiryWarning = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{76 * time.Hour, "3d 4h"},
+		{5*time.Hour + 30*time.Minute, "5h 30m"},
+		{45 * time.Minute, "45m"},
+		{-2 * time.Hour, "2h 0m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ActionKind is one kind of outbound action against the site.
+type ActionKind string
+
+const (
+	ActionProfileView ActionKind = "profile_view" // Opened a profile page
+	ActionSearchPage  ActionKind = "search_page"  // Read a page of search results
+	ActionInvite      ActionKind = "invite"       // Sent a connection request
+	ActionMessage     ActionKind = "message"      // Sent a follow-up message
+	ActionWithdrawal  ActionKind = "withdrawal"   // Withdrew a pending connection request
+)
+
+// AllActionKinds lists every action kind, in pipeline order.
+var AllActionKinds = []ActionKind{ActionSearchPage, ActionProfileView, ActionInvite, ActionMessage, ActionWithdrawal}
+
+// RecordAction appends an action to the ledger for the campaign in use, tagged with the current
+// run ID. profileURL may be empty for actions not aimed at one profile.
+func RecordAction(db *sql.DB, kind ActionKind, profileURL string, count int, detail string) error {
+	var profileID sql.NullInt64
+	if profileURL != "" {
+		err := db.QueryRow("SELECT id FROM profiles WHERE "+urlMatch, urlArgs(profileURL)...).Scan(&profileID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, profileURL)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	campaign := campaignID
+	if campaign == 0 {
+		campaign = defaultCampaignID
+	}
+
+	_, err := db.Exec(`
+        INSERT INTO actions (kind, profile_id, campaign_id, count, detail, run_id, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `, string(kind), profileID, campaign, count, detail, runID, time.Now())
+	return err
+}
+
+// CountActions returns the total count of kind actions since the given time, across every campaign:
+// the site limits the account, not a campaign.
+func CountActions(db *sql.DB, kind ActionKind, since time.Time) (int, error) {
+	return countActions(db, kind, since, "", nil)
+}
+
+// CountCampaignActions returns the total count of kind actions since the given time, for the
+// campaign in use only.
+func CountCampaignActions(db *sql.DB, kind ActionKind, since time.Time) (int, error) {
+	scope, scopeArgs := CampaignScope("campaign_id")
+	return countActions(db, kind, since, scope, scopeArgs)
+}
+
+// countActions sums the ledger's kind actions since the given time, narrowed by an extra condition.
+// created_at holds host-local times as text and compares as text, so since is converted to match.
+func countActions(db *sql.DB, kind ActionKind, since time.Time, scope string, scopeArgs []any) (int, error) {
+	since = since.In(time.Local)
+	var total int
+	err := db.QueryRow(`
+        SELECT COALESCE(SUM(count), 0)
+        FROM actions
+        WHERE kind = ? AND created_at >= ?`+scope,
+		append([]any{string(kind), since}, scopeArgs...)...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count %s actions: %w", kind, err)
+	}
+	return total, nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCountActions(t *testing.T) {
+	db := openTestDB(t)
+	start := time.Now().Add(-time.Minute)
+
+	if _, err := UseCampaign(db, "hiring"); err != nil {
+		t.Fatalf("UseCampaign: %v", err)
+	}
+	record := func(kind ActionKind, count int) {
+		t.Helper()
+		if err := RecordAction(db, kind, "", count, ""); err != nil {
+			t.Fatalf("RecordAction(%s): %v", kind, err)
+		}
+	}
+	record(ActionInvite, 1)
+	record(ActionInvite, 1)
+	record(ActionSearchPage, 7)
+
+	if _, err := UseCampaign(db, "events"); err != nil {
+		t.Fatalf("UseCampaign: %v", err)
+	}
+	record(ActionInvite, 1)
+
+	tests := []struct {
+		name  string
+		count func() (int, error)
+		want  int
+	}{
+		{"invites across campaigns", func() (int, error) { return CountActions(db, ActionInvite, start) }, 3},
+		{"invites of the campaign in use", func() (int, error) { return CountCampaignActions(db, ActionInvite, start) }, 1},
+		{"profiles added by search pages", func() (int, error) { return CountActions(db, ActionSearchPage, start) }, 7},
+		{"search pages of the campaign in use", func() (int, error) { return CountCampaignActions(db, ActionSearchPage, start) }, 0},
+		{"since in another timezone", func() (int, error) {
+			return CountActions(db, ActionInvite, start.In(time.FixedZone("UTC+14", 14*3600)))
+		}, 3},
+		{"nothing since later", func() (int, error) { return CountActions(db, ActionInvite, time.Now().Add(time.Minute)) }, 0},
+		{"kind never recorded", func() (int, error) { return CountActions(db, ActionMessage, start) }, 0},
+	}
+	for _, tt := range tests {
+		got, err := tt.count()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordActionUnknownProfile(t *testing.T) {
+	db := openTestDB(t)
+	err := RecordAction(db, ActionProfileView, "https://www.linkedin.com/in/nobody", 1, "")
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("RecordAction error = %v, want ErrProfileNotFound", err)
+	}
+}
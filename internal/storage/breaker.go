@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BreakerTrip is one time the circuit breaker stopped the bot. Trips apply to the whole account, not a campaign.
+type BreakerTrip struct {
+	ID        int64
+	Signal    string // What tripped it, e.g. "checkpoint"
+	Detail    string // Where it was seen, or the last failure
+	RunID     string
+	TrippedAt time.Time
+	Until     time.Time    // End of the cooldown
+	ClearedAt sql.NullTime // Set when the trip was reset by hand
+}
+
+// RecordBreakerTrip stores a trip by the current run that lasts until the given time.
+func RecordBreakerTrip(db *sql.DB, signal, detail string, until time.Time) (*BreakerTrip, error) {
+	t := &BreakerTrip{Signal: signal, Detail: detail, RunID: runID, TrippedAt: time.Now(), Until: until}
+	res, err := db.Exec(`
+        INSERT INTO breaker_trips (signal, detail, run_id, tripped_at, until)
+        VALUES (?, ?, ?, ?, ?)
+    `, t.Signal, t.Detail, t.RunID, t.TrippedAt, t.Until)
+	if err != nil {
+		return nil, err
+	}
+	t.ID, err = res.LastInsertId()
+	return t, err
+}
+
+// GetActiveBreakerTrip returns the trip whose cooldown lasts longest past now and was not cleared,
+// or nil if there is none.
+func GetActiveBreakerTrip(db *sql.DB, now time.Time) (*BreakerTrip, error) {
+	trips, err := queryBreakerTrips(db, "WHERE cleared_at IS NULL ORDER BY id DESC", -1)
+	if err != nil {
+		return nil, err
+	}
+	var active *BreakerTrip
+	for i := range trips {
+		if trips[i].Until.After(now) && (active == nil || trips[i].Until.After(active.Until)) {
+			active = &trips[i]
+		}
+	}
+	return active, nil
+}
+
+// GetBreakerTrips returns the most recent trips, newest first.
+func GetBreakerTrips(db *sql.DB, limit int) ([]BreakerTrip, error) {
+	return queryBreakerTrips(db, "ORDER BY id DESC", limit)
+}
+
+// ClearBreakerTrips marks every trip still cooling down as cleared, returning how many were.
+func ClearBreakerTrips(db *sql.DB, now time.Time) (int, error) {
+	active, err := queryBreakerTrips(db, "WHERE cleared_at IS NULL", -1)
+	if err != nil {
+		return 0, err
+	}
+	cleared := 0
+	for _, t := range active {
+		if !t.Until.After(now) {
+			continue
+		}
+		if _, err := db.Exec("UPDATE breaker_trips SET cleared_at = ? WHERE id = ?", now, t.ID); err != nil {
+			return cleared, err
+		}
+		cleared++
+	}
+	return cleared, nil
+}
+
+// queryBreakerTrips returns the trips selected by clause (filter and order), at most limit (-1 for all)
+func queryBreakerTrips(db *sql.DB, clause string, limit int) ([]BreakerTrip, error) {
+	rows, err := db.Query(`
+        SELECT id, signal, detail, run_id, tripped_at, until, cleared_at
+        FROM breaker_trips `+clause+`
+        LIMIT ?
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trips []BreakerTrip
+	for rows.Next() {
+		var t BreakerTrip
+		if err := rows.Scan(&t.ID, &t.Signal, &t.Detail, &t.RunID, &t.TrippedAt, &t.Until, &t.ClearedAt); err != nil {
+			return nil, err
+		}
+		trips = append(trips, t)
+	}
+	return trips, rows.Err()
+}
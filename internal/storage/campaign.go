@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DefaultCampaign is the campaign built from the .env settings. Profiles stored before
+// campaigns existed belong to it.
+const DefaultCampaign = "default"
+
+// defaultCampaignID is the id migration 0005 gives the default campaign
+const defaultCampaignID = 1
+
+// campaignID scopes profile queries and new profiles to one campaign. 0 means all campaigns.
+var campaignID int64
+
+// Campaign is a row of the campaigns table.
+type Campaign struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+// UseCampaign looks up the named campaign, creating it on first use, and scopes every later
+// profile query, new profile and limit count to it.
+func UseCampaign(db *sql.DB, name string) (*Campaign, error) {
+	if name == "" {
+		name = DefaultCampaign
+	}
+	if _, err := db.Exec("INSERT OR IGNORE INTO campaigns (name, created_at) VALUES (?, ?)", name, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var c Campaign
+	err := db.QueryRow("SELECT id, name, created_at FROM campaigns WHERE name = ?", name).Scan(&c.ID, &c.Name, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	campaignID = c.ID
+	return &c, nil
+}
+
+// CampaignScope returns a condition limiting a profiles query to the campaign in use,
+// to be appended after a WHERE clause, and its arguments. Both are empty when no campaign is in use.
+// column is the campaign_id column, qualified if the query joins other tables.
+func CampaignScope(column string) (string, []any) {
+	if campaignID == 0 {
+		return "", nil
+	}
+	return " AND " + column + " = ?", []any{campaignID}
+}
+
+// GetCampaigns returns every campaign with its profile count, oldest first.
+func GetCampaigns(db *sql.DB) ([]Campaign, map[int64]int, error) {
+	rows, err := db.Query(`
+        SELECT c.id, c.name, c.created_at, COUNT(p.id)
+        FROM campaigns c
+        LEFT JOIN profiles p ON p.campaign_id = c.id
+        GROUP BY c.id
+        ORDER BY c.id ASC
+    `)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []Campaign
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var c Campaign
+		var n int
+		if err := rows.Scan(&c.ID, &c.Name, &c.CreatedAt, &n); err != nil {
+			return nil, nil, err
+		}
+		campaigns = append(campaigns, c)
+		counts[c.ID] = n
+	}
+	return campaigns, counts, rows.Err()
+}
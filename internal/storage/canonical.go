@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+)
+
+// canonicalSite is the scheme/host and profile path prefix canonical URLs are rewritten to.
+var canonicalSite = struct {
+	scheme      string
+	host        string
+	profilePath string
+}{scheme: "https", host: "www.linkedin.com", profilePath: "/in/"}
+
+// ConfigureCanonical sets the site that profile URLs are normalized to (see CanonicalURL).
+// baseURL is the configured site root, profilePath the profile path prefix (e.g. "/in/").
+func ConfigureCanonical(baseURL, profilePath string) {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		canonicalSite.scheme = strings.ToLower(u.Scheme)
+		canonicalSite.host = strings.ToLower(u.Host)
+	}
+	if profilePath != "" {
+		canonicalSite.profilePath = strings.ToLower(profilePath)
+	}
+}
+
+// CanonicalURL normalizes a profile URL so variants of the same person map to one row:
+//   - scheme and host are rewritten to the configured site when the host matches it
+//     (ignoring "www."), otherwise just lowercased
+//   - query string and fragment are dropped
+//   - the path is lowercased and cut after the profile slug, which removes locale
+//     suffixes (/in/foo/en) and sub-pages (/in/foo/details/experience)
+//   - no trailing slash
+//
+// Unparseable input is returned trimmed but otherwise unchanged.
+func CanonicalURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	if strings.TrimPrefix(host, "www.") == strings.TrimPrefix(canonicalSite.host, "www.") {
+		scheme, host = canonicalSite.scheme, canonicalSite.host
+	}
+
+	path := strings.ToLower(u.EscapedPath())
+	prefix := canonicalSite.profilePath
+	if strings.HasPrefix(path, prefix) {
+		slug, _, _ := strings.Cut(strings.TrimPrefix(path, prefix), "/")
+		path = prefix + slug
+	}
+	path = strings.TrimRight(path, "/")
+
+	return scheme + "://" + host + path
+}
+
+// isProfileURL reports whether a canonical URL is a profile (a slug under the profile path) on the configured site
+func isProfileURL(canonical string) bool {
+	prefix := canonicalSite.scheme + "://" + canonicalSite.host + canonicalSite.profilePath
+	return strings.HasPrefix(canonical, prefix) && strings.Trim(strings.TrimPrefix(canonical, prefix), "/") != ""
+}
+
+// urlMatch is the WHERE fragment used for URL lookups. Besides the canonical form it also
+// matches the raw input, so rows stored before canonicalization (not yet merged by
+// DedupeProfiles) are still found.
+const urlMatch = "url IN (?, ?)"
+
+// urlArgs returns the arguments for urlMatch
+func urlArgs(rawURL string) []any {
+	return []any{CanonicalURL(rawURL), strings.TrimSpace(rawURL)}
+}
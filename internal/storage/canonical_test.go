@@ -0,0 +1,45 @@
+package storage
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://www.linkedin.com/in/jane-doe", "https://www.linkedin.com/in/jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/", "https://www.linkedin.com/in/jane-doe"},
+		{"http://linkedin.com/in/jane-doe", "https://www.linkedin.com/in/jane-doe"},
+		{"https://WWW.LinkedIn.com/in/Jane-Doe/", "https://www.linkedin.com/in/jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe?miniProfileUrn=abc&trk=search#top", "https://www.linkedin.com/in/jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/en", "https://www.linkedin.com/in/jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/details/experience/", "https://www.linkedin.com/in/jane-doe"},
+		{"  https://www.linkedin.com/in/jane-doe  ", "https://www.linkedin.com/in/jane-doe"},
+		{"https://www.linkedin.com/in/j%C3%B6rg", "https://www.linkedin.com/in/j%c3%b6rg"},
+		{"https://Example.com/Company/Acme/", "https://example.com/company/acme"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.in); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalURLConfiguredSite(t *testing.T) {
+	saved := canonicalSite
+	t.Cleanup(func() { canonicalSite = saved })
+	ConfigureCanonical("http://127.0.0.1:8090", "/in/")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"http://127.0.0.1:8090/in/Test-User-01/", "http://127.0.0.1:8090/in/test-user-01"},
+		{"http://127.0.0.1:8090/in/test-user-01?trk=x", "http://127.0.0.1:8090/in/test-user-01"},
+		{"https://www.linkedin.com/in/jane-doe/", "https://www.linkedin.com/in/jane-doe"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.in); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
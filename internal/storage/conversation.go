@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// MessageSender tells who wrote a conversation message.
+type MessageSender string
+
+const (
+	SenderMe      MessageSender = "me"      // The bot's account
+	SenderContact MessageSender = "contact" // The profile on the other side
+)
+
+// ConversationMessage is one message of an inbox thread.
+type ConversationMessage struct {
+	Sender     MessageSender
+	SenderName string
+	SentAt     string // As shown in the thread (datetime attribute or label); may be empty
+	Text       string
+	SyncedAt   time.Time // When the message was first stored
+}
+
+// SaveConversation stores the thread at threadURL as a conversation with the profile at profileURL
+// and adds the messages not stored yet (same sender, timestamp and text). Returns how many were new.
+func SaveConversation(db *sql.DB, profileURL, threadURL string, messages []ConversationMessage) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var profileID int64
+	err = tx.QueryRow("SELECT id FROM profiles WHERE "+urlMatch, urlArgs(profileURL)...).Scan(&profileID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: %s", ErrProfileNotFound, profileURL)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+        INSERT INTO conversations (profile_id, thread_url, created_at, synced_at) VALUES (?, ?, ?, ?)
+        ON CONFLICT(thread_url) DO UPDATE SET profile_id = excluded.profile_id, synced_at = excluded.synced_at
+    `, profileID, threadURL, now, now)
+	if err != nil {
+		return 0, err
+	}
+	var conversationID int64
+	if err := tx.QueryRow("SELECT id FROM conversations WHERE thread_url = ?", threadURL).Scan(&conversationID); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, m := range messages {
+		result, err := tx.Exec(`
+            INSERT OR IGNORE INTO messages (conversation_id, sender, sender_name, sent_at, text, synced_at)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `, conversationID, string(m.Sender), m.SenderName, m.SentAt, m.Text, now)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// GetConversationMessages returns every stored message exchanged with a profile, oldest first.
+func GetConversationMessages(db *sql.DB, profileURL string) ([]ConversationMessage, error) {
+	rows, err := db.Query(`
+        SELECT m.sender, m.sender_name, m.sent_at, m.text, m.synced_at
+        FROM messages m
+        JOIN conversations c ON c.id = m.conversation_id
+        JOIN profiles p ON p.id = c.profile_id
+        WHERE p.`+urlMatch+`
+        ORDER BY m.id ASC
+    `, urlArgs(profileURL)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []ConversationMessage
+	for rows.Next() {
+		var m ConversationMessage
+		var sender string
+		if err := rows.Scan(&sender, &m.SenderName, &m.SentAt, &m.Text, &m.SyncedAt); err != nil {
+			return nil, err
+		}
+		m.Sender = MessageSender(sender)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// MarkReplied moves a contacted profile to 'replied' and halts its follow-up sequence in one step.
+// Profiles that cannot move to 'replied' (never contacted, already replied, opted out, ...)
+// are left alone. Returns true if the status changed.
+func MarkReplied(db *sql.DB, url, reason string) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	var current string
+	err = tx.QueryRow("SELECT id, status FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id, &current)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return false, err
+	}
+	from := ProfileStatus(current)
+	if !CanTransition(from, StatusReplied) {
+		return false, nil
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+        UPDATE profiles SET status = ?, updated_at = ?, next_action_at = NULL,
+            sequence_halted_at = COALESCE(sequence_halted_at, ?)
+        WHERE id = ?
+    `, string(StatusReplied), now, now, id)
+	if err != nil {
+		return false, err
+	}
+	if err := recordEvent(tx, id, from, StatusReplied, reason, now); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	logger.Info("✅ Updated status", logging.Profile(url), "status", StatusReplied)
+	return true, nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	janeURL    = "https://www.linkedin.com/in/jane-doe/"
+	janeThread = "https://www.linkedin.com/messaging/thread/2-abc/"
+)
+
+func TestSaveConversationDedupes(t *testing.T) {
+	db := openTestDB(t)
+	addProfile(t, db, Profile{URL: janeURL})
+
+	first := []ConversationMessage{
+		{Sender: SenderMe, SenderName: "Me", SentAt: "2026-03-02T10:00", Text: "Thanks for connecting!"},
+		{Sender: SenderContact, SenderName: "Jane Doe", SentAt: "2026-03-02T11:00", Text: "Likewise"},
+	}
+	later := append(first,
+		ConversationMessage{Sender: SenderContact, SenderName: "Jane Doe", SentAt: "2026-03-03T09:00", Text: "Likewise"}, // Same text, later
+		ConversationMessage{Sender: SenderMe, SenderName: "Me", SentAt: "2026-03-02T11:00", Text: "Likewise"},            // Same text and time, other sender
+	)
+
+	tests := []struct {
+		name      string
+		profile   string
+		messages  []ConversationMessage
+		wantAdded int
+		wantTotal int
+	}{
+		{"first sync", janeURL, first, 2, 2},
+		{"same thread again", janeURL, first, 0, 2},
+		{"new messages only", janeURL, later, 2, 4},
+		{"profile URL variant", "https://linkedin.com/in/Jane-Doe?trk=inbox", later, 0, 4},
+		{"no messages", janeURL, nil, 0, 4},
+	}
+	for _, tt := range tests {
+		added, err := SaveConversation(db, tt.profile, janeThread, tt.messages)
+		if err != nil {
+			t.Fatalf("%s: SaveConversation: %v", tt.name, err)
+		}
+		stored, err := GetConversationMessages(db, janeURL)
+		if err != nil {
+			t.Fatalf("%s: GetConversationMessages: %v", tt.name, err)
+		}
+		if added != tt.wantAdded || len(stored) != tt.wantTotal {
+			t.Errorf("%s: added %d, %d stored; want %d, %d", tt.name, added, len(stored), tt.wantAdded, tt.wantTotal)
+		}
+	}
+
+	var conversations int
+	if err := db.QueryRow("SELECT COUNT(*) FROM conversations").Scan(&conversations); err != nil || conversations != 1 {
+		t.Errorf("%d conversations (%v), want the thread stored once", conversations, err)
+	}
+	stored, err := GetConversationMessages(db, janeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range stored {
+		if m.Sender != later[i].Sender || m.SentAt != later[i].SentAt || m.Text != later[i].Text {
+			t.Errorf("message %d = %+v, want %+v", i, m, later[i])
+		}
+	}
+}
+
+func TestSaveConversationUnknownProfile(t *testing.T) {
+	db := openTestDB(t)
+	_, err := SaveConversation(db, janeURL, janeThread, []ConversationMessage{{Sender: SenderContact, Text: "Hi"}})
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("SaveConversation error = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestMarkReplied(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        []ProfileStatus // Statuses the profile goes through after being found
+		wantChanged bool
+		want        ProfileStatus
+	}{
+		{"invited", []ProfileStatus{StatusInvited}, true, StatusReplied},
+		{"messaged", []ProfileStatus{StatusInvited, StatusMessaged}, true, StatusReplied},
+		{"never contacted", nil, false, StatusFound},
+		{"already replied", []ProfileStatus{StatusInvited, StatusReplied}, false, StatusReplied},
+		{"opted out", []ProfileStatus{StatusOptedOut}, false, StatusOptedOut},
+	}
+	for _, tt := range tests {
+		db := openTestDB(t)
+		addProfile(t, db, Profile{URL: janeURL})
+		for _, status := range tt.path {
+			if err := UpdateStatus(db, janeURL, status, "test setup"); err != nil {
+				t.Fatalf("%s: UpdateStatus(%s): %v", tt.name, status, err)
+			}
+		}
+
+		changed, err := MarkReplied(db, janeURL, "test: replied")
+		if err != nil || changed != tt.wantChanged {
+			t.Errorf("%s: MarkReplied = %v, %v; want %v", tt.name, changed, err, tt.wantChanged)
+		}
+		p, err := GetProfile(db, janeURL)
+		if err != nil {
+			t.Fatalf("GetProfile: %v", err)
+		}
+		if p.Status != tt.want {
+			t.Errorf("%s: status %s, want %s", tt.name, p.Status, tt.want)
+		}
+		if tt.wantChanged && (!p.SequenceHaltedAt.Valid || p.NextActionAt.Valid) {
+			t.Errorf("%s: sequence halted %v, next action %v; want halted with nothing due", tt.name, p.SequenceHaltedAt, p.NextActionAt)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// DedupeResult summarizes a DedupeProfiles run.
+type DedupeResult struct {
+	Groups    int // Canonical URLs that had more than one row
+	Removed   int // Duplicate rows merged away
+	Rewritten int // Rows whose URL was rewritten to its canonical form
+}
+
+// DedupeProfiles merges rows whose URLs share a canonical form and rewrites every
+// remaining URL to canonical form, all in one transaction.
+// The surviving row keeps the most advanced status (see statusRank), the earliest
+// created_at, and any details missing from it are filled in from the merged rows.
+// Events, dry-run results, conversations and outbound messages of merged rows are re-pointed to the survivor.
+func DedupeProfiles(db *sql.DB) (*DedupeResult, error) {
+	profiles, err := queryProfiles(db, "SELECT "+profileColumns+" FROM profiles ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]Profile)
+	var order []string
+	for _, p := range profiles {
+		key := CanonicalURL(p.URL)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &DedupeResult{}
+	for _, key := range order {
+		group := groups[key]
+		survivor, others := pickSurvivor(group)
+
+		if len(others) == 0 {
+			if survivor.URL != key {
+				if _, err := tx.Exec("UPDATE profiles SET url = ? WHERE id = ?", key, survivor.ID); err != nil {
+					return nil, fmt.Errorf("rewrite %s: %w", survivor.URL, err)
+				}
+				result.Rewritten++
+			}
+			continue
+		}
+
+		merged := mergeProfiles(survivor, others)
+		for _, o := range others {
+			if _, err := tx.Exec("UPDATE profile_events SET profile_id = ? WHERE profile_id = ?", survivor.ID, o.ID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("UPDATE dry_run_results SET profile_id = ? WHERE profile_id = ?", survivor.ID, o.ID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("UPDATE conversations SET profile_id = ? WHERE profile_id = ?", survivor.ID, o.ID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("UPDATE outbound_messages SET profile_id = ? WHERE profile_id = ?", survivor.ID, o.ID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("UPDATE actions SET profile_id = ? WHERE profile_id = ?", survivor.ID, o.ID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("DELETE FROM profiles WHERE id = ?", o.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err := tx.Exec(`
+            UPDATE profiles SET
+                url = ?, full_name = ?, first_name = ?, last_name = ?, headline = ?, company = ?, location = ?,
+                search_keyword = ?, search_page = ?, search_rank = ?, created_at = ?,
+                sequence_step = ?, sequence_halted_at = ?
+            WHERE id = ?
+        `, key, merged.FullName, merged.FirstName, merged.LastName, merged.Headline, merged.Company, merged.Location,
+			merged.SearchKeyword, merged.SearchPage, merged.SearchRank, merged.CreatedAt,
+			merged.SequenceStep, merged.SequenceHaltedAt, survivor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("merge %s: %w", key, err)
+		}
+
+		reason := fmt.Sprintf("dedupe: merged %d duplicate row(s)", len(others))
+		if err := recordEvent(tx, survivor.ID, survivor.Status, survivor.Status, reason, time.Now()); err != nil {
+			return nil, err
+		}
+
+		logger.Info("🔗 Merged duplicate rows", logging.Profile(key), "rows", len(group), "status", survivor.Status)
+		result.Groups++
+		result.Removed += len(others)
+		if survivor.URL != key {
+			result.Rewritten++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pickSurvivor returns the row with the most advanced status (oldest first on ties) and the rest
+func pickSurvivor(group []Profile) (Profile, []Profile) {
+	sorted := append([]Profile(nil), group...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := statusRank[sorted[i].Status], statusRank[sorted[j].Status]
+		if ri != rj {
+			return ri > rj
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted[0], sorted[1:]
+}
+
+// mergeProfiles fills empty fields of survivor from the other rows and keeps the earliest created_at.
+// The furthest sequence step wins, and a halted sequence stays halted.
+func mergeProfiles(survivor Profile, others []Profile) Profile {
+	merged := survivor
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	for _, o := range others {
+		fill(&merged.FullName, o.FullName)
+		fill(&merged.FirstName, o.FirstName)
+		fill(&merged.LastName, o.LastName)
+		fill(&merged.Headline, o.Headline)
+		fill(&merged.Company, o.Company)
+		fill(&merged.Location, o.Location)
+		if merged.SearchKeyword == "" {
+			merged.SearchKeyword, merged.SearchPage, merged.SearchRank = o.SearchKeyword, o.SearchPage, o.SearchRank
+		}
+		if o.CreatedAt.Before(merged.CreatedAt) {
+			merged.CreatedAt = o.CreatedAt
+		}
+		if o.SequenceStep > merged.SequenceStep {
+			merged.SequenceStep = o.SequenceStep
+		}
+		if !merged.SequenceHaltedAt.Valid {
+			merged.SequenceHaltedAt = o.SequenceHaltedAt
+		}
+	}
+	return merged
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// insertRaw stores a row as versions before canonicalization did, bypassing AddProfile
+func insertRaw(t *testing.T, db *sql.DB, url string, status ProfileStatus, company string, createdAt time.Time) {
+	t.Helper()
+	_, err := db.Exec(`
+        INSERT INTO profiles (url, status, company, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+    `, url, string(status), company, createdAt, createdAt)
+	if err != nil {
+		t.Fatalf("insert %s: %v", url, err)
+	}
+}
+
+func TestDedupeProfiles(t *testing.T) {
+	db := openTestDB(t)
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	insertRaw(t, db, "https://www.linkedin.com/in/jane-doe/", StatusFound, "", day.Add(2*time.Hour))
+	insertRaw(t, db, "https://linkedin.com/in/Jane-Doe?trk=x", StatusMessaged, "", day.Add(time.Hour))
+	insertRaw(t, db, "https://www.linkedin.com/in/jane-doe/en", StatusInvited, "Acme", day)
+	insertRaw(t, db, "https://www.linkedin.com/in/john-doe/", StatusFound, "", day)
+	insertRaw(t, db, "https://www.linkedin.com/in/solo", StatusFound, "", day)
+
+	result, err := DedupeProfiles(db)
+	if err != nil {
+		t.Fatalf("DedupeProfiles: %v", err)
+	}
+	want := DedupeResult{Groups: 1, Removed: 2, Rewritten: 2}
+	if *result != want {
+		t.Errorf("DedupeProfiles = %+v, want %+v", *result, want)
+	}
+
+	tests := []struct {
+		url     string
+		status  ProfileStatus
+		company string
+		created time.Time
+	}{
+		{"https://www.linkedin.com/in/jane-doe", StatusMessaged, "Acme", day},
+		{"https://www.linkedin.com/in/john-doe", StatusFound, "", day},
+		{"https://www.linkedin.com/in/solo", StatusFound, "", day},
+	}
+	for _, tt := range tests {
+		var p Profile
+		err := db.QueryRow("SELECT url, status, company, created_at FROM profiles WHERE url = ?", tt.url).
+			Scan(&p.URL, &p.Status, &p.Company, &p.CreatedAt)
+		if err != nil {
+			t.Errorf("%s: %v", tt.url, err)
+			continue
+		}
+		if p.Status != tt.status || p.Company != tt.company || !p.CreatedAt.Equal(tt.created) {
+			t.Errorf("%s = (%s, %q, %s), want (%s, %q, %s)", tt.url, p.Status, p.Company, p.CreatedAt, tt.status, tt.company, tt.created)
+		}
+	}
+
+	// A second run finds nothing left to do
+	again, err := DedupeProfiles(db)
+	if err != nil {
+		t.Fatalf("DedupeProfiles again: %v", err)
+	}
+	if *again != (DedupeResult{}) {
+		t.Errorf("second DedupeProfiles = %+v, want nothing", *again)
+	}
+}
+
+func TestPickSurvivor(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []ProfileStatus
+		want     int64
+	}{
+		{"most advanced status wins", []ProfileStatus{StatusFound, StatusMessaged, StatusInvited}, 2},
+		{"oldest row wins a tie", []ProfileStatus{StatusInvited, StatusInvited}, 1},
+		{"suppression beats everything", []ProfileStatus{StatusReplied, StatusSuppressed, StatusOptedOut}, 2},
+	}
+	for _, tt := range tests {
+		var group []Profile
+		for i, s := range tt.statuses {
+			group = append(group, Profile{ID: int64(i + 1), Status: s})
+		}
+		survivor, others := pickSurvivor(group)
+		if survivor.ID != tt.want || len(others) != len(group)-1 {
+			t.Errorf("%s: survivor %d with %d others, want %d with %d", tt.name, survivor.ID, len(others), tt.want, len(group)-1)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DryRunResult is one action a dry run walked through without performing it.
+type DryRunResult struct {
+	ProfileURL string
+	RunID      string
+	Action     string // "connect" or "message"
+	Outcome    string // What would have happened, e.g. "would_invite" or the status that would have been set
+	Text       string // Rendered note/message, or the reason for the outcome
+	CreatedAt  time.Time
+}
+
+// RecordDryRun stores what an action would have done. It never touches the profile itself.
+func RecordDryRun(db *sql.DB, url, action, outcome, text string) error {
+	var id int64
+	err := db.QueryRow("SELECT id FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+        INSERT INTO dry_run_results (run_id, profile_id, action, outcome, text, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `, runID, id, action, outcome, text, time.Now())
+	return err
+}
+
+// GetDryRunResults returns the results recorded by one run, oldest first.
+func GetDryRunResults(db *sql.DB, run string) ([]DryRunResult, error) {
+	rows, err := db.Query(`
+        SELECT p.url, r.run_id, r.action, r.outcome, r.text, r.created_at
+        FROM dry_run_results r
+        JOIN profiles p ON p.id = r.profile_id
+        WHERE r.run_id = ?
+        ORDER BY r.id ASC
+    `, run)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []DryRunResult
+	for rows.Next() {
+		var r DryRunResult
+		if err := rows.Scan(&r.ProfileURL, &r.RunID, &r.Action, &r.Outcome, &r.Text, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// openEmptyDB opens a database file without a schema in a temporary working directory
+func openEmptyDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := OpenDB()
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// tableExists reports whether the schema has a table called name
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func TestMigrateAppliesInOrder(t *testing.T) {
+	db := openEmptyDB(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	ran, err := Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(ran) != len(migrations) {
+		t.Fatalf("applied %d migrations, want all %d", len(ran), len(migrations))
+	}
+	for i, m := range ran {
+		if m.Version != i+1 {
+			t.Errorf("migration %d applied is %s, want version %d", i+1, m.Name, i+1)
+		}
+	}
+
+	rows, err := db.Query("SELECT version FROM schema_version ORDER BY rowid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var recorded []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, v)
+	}
+	if len(recorded) != len(migrations) {
+		t.Errorf("schema_version has %d rows, want %d", len(recorded), len(migrations))
+	}
+	for i, v := range recorded {
+		if v != i+1 {
+			t.Errorf("schema_version row %d is version %d, want %d", i+1, v, i+1)
+		}
+	}
+	if version, err := SchemaVersion(db); err != nil || version != len(migrations) {
+		t.Errorf("SchemaVersion = %d, %v; want %d", version, err, len(migrations))
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	db := openEmptyDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	addProfile(t, db, Profile{URL: "https://www.linkedin.com/in/jane-doe/"})
+
+	ran, err := Migrate(db)
+	if err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("second Migrate applied %d migrations, want none", len(ran))
+	}
+	states, err := GetMigrationStatus(db)
+	if err != nil {
+		t.Fatalf("GetMigrationStatus: %v", err)
+	}
+	for _, s := range states {
+		if !s.Applied {
+			t.Errorf("%s is not applied", s.Name)
+		}
+	}
+	var rows, profiles int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&rows); err != nil || rows != len(states) {
+		t.Errorf("schema_version has %d rows (%v), want %d", rows, err, len(states))
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM profiles").Scan(&profiles); err != nil || profiles != 1 {
+		t.Errorf("profiles has %d rows (%v) after the re-run, want 1", profiles, err)
+	}
+}
+
+func TestMigrateAdoptsLegacyDatabase(t *testing.T) {
+	db := openEmptyDB(t)
+	// The schema InitDB created before migrations existed
+	_, err := db.Exec(`
+        CREATE TABLE profiles (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            url TEXT UNIQUE NOT NULL,
+            status TEXT NOT NULL DEFAULT 'found',
+            created_at DATETIME NOT NULL,
+            updated_at DATETIME NOT NULL
+        );
+        INSERT INTO profiles (url, status, created_at, updated_at)
+        VALUES ('https://www.linkedin.com/in/jane-doe/', 'invited', datetime('now'), datetime('now'));
+    `)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	p, err := GetProfile(db, "https://www.linkedin.com/in/jane-doe/")
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+	if p.Status != StatusInvited {
+		t.Errorf("legacy profile has status %s, want invited", p.Status)
+	}
+}
+
+func TestApplyMigrationRollsBack(t *testing.T) {
+	db := openEmptyDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	broken := Migration{Version: 9999, Name: "9999_broken", SQL: "CREATE TABLE half_done (id INTEGER); INSERT INTO missing VALUES (1);"}
+
+	if err := applyMigration(db, broken); err == nil {
+		t.Fatal("applyMigration of a failing migration: no error")
+	}
+	if tableExists(t, db, "half_done") {
+		t.Error("the failed migration's first statement was kept")
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		t.Fatalf("appliedVersions: %v", err)
+	}
+	if _, ok := applied[broken.Version]; ok {
+		t.Error("the failed migration is recorded as applied")
+	}
+}
@@ -0,0 +1,24 @@
+-- Initial schema. Uses IF NOT EXISTS so databases created before
+-- migrations existed are adopted as version 1 without changes.
+CREATE TABLE IF NOT EXISTS profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT UNIQUE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'found',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_url ON profiles(url);
+CREATE INDEX IF NOT EXISTS idx_status ON profiles(status);
+CREATE INDEX IF NOT EXISTS idx_created_at ON profiles(created_at);
+CREATE INDEX IF NOT EXISTS idx_updated_at ON profiles(updated_at);
+
+CREATE TABLE IF NOT EXISTS profile_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL REFERENCES profiles(id),
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    run_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_events_profile ON profile_events(profile_id);
@@ -0,0 +1,12 @@
+-- Rich profile records: scraped identity fields plus where search found the profile.
+ALTER TABLE profiles ADD COLUMN full_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN first_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN last_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN headline TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN company TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN location TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN search_keyword TEXT NOT NULL DEFAULT '';
+ALTER TABLE profiles ADD COLUMN search_page INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE profiles ADD COLUMN search_rank INTEGER NOT NULL DEFAULT 0;
+-- Last time the details were refreshed from a profile page (NULL = never visited)
+ALTER TABLE profiles ADD COLUMN refreshed_at DATETIME;
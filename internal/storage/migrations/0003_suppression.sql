@@ -0,0 +1,10 @@
+-- Do-not-contact list. Profiles matching any entry are never invited or messaged.
+-- value is normalized: canonical URL for kind 'url', lowercase with single spaces otherwise.
+CREATE TABLE suppression (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL CHECK (kind IN ('url', 'name', 'company')),
+    value TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    UNIQUE (kind, value)
+);
@@ -0,0 +1,12 @@
+-- What a -dry-run invocation would have done. Kept apart from profiles/profile_events
+-- so dry runs never change a profile's status or count towards daily limits.
+CREATE TABLE dry_run_results (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL DEFAULT '',
+    profile_id INTEGER NOT NULL REFERENCES profiles(id),
+    action TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX idx_dry_run_results_run ON dry_run_results(run_id);
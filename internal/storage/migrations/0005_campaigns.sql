@@ -0,0 +1,11 @@
+-- Named outreach campaigns. Every profile belongs to the campaign whose search found it;
+-- profiles stored before campaigns existed belong to 'default' (id 1).
+CREATE TABLE campaigns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    created_at DATETIME NOT NULL
+);
+INSERT INTO campaigns (id, name, created_at) VALUES (1, 'default', CURRENT_TIMESTAMP);
+
+ALTER TABLE profiles ADD COLUMN campaign_id INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX idx_profiles_campaign ON profiles(campaign_id);
@@ -0,0 +1,12 @@
+-- Multi-step follow-up sequences.
+-- sequence_step: follow-up steps sent so far.
+-- next_action_at: when the next step is due (NULL: nothing scheduled; for 'invited' profiles
+--   it means "check for acceptance").
+-- sequence_halted_at: set when the contact replied; no further steps are sent.
+ALTER TABLE profiles ADD COLUMN sequence_step INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE profiles ADD COLUMN next_action_at DATETIME;
+ALTER TABLE profiles ADD COLUMN sequence_halted_at DATETIME;
+CREATE INDEX idx_profiles_next_action ON profiles(next_action_at);
+
+-- Profiles messaged before sequences existed received the single follow-up
+UPDATE profiles SET sequence_step = 1 WHERE status = 'messaged';
@@ -0,0 +1,21 @@
+-- Inbox threads with contacts from the profiles table, and the messages read from them.
+-- sent_at is the timestamp as the thread shows it (datetime attribute or label), '' if none.
+CREATE TABLE conversations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL REFERENCES profiles(id),
+    thread_url TEXT UNIQUE NOT NULL,
+    created_at DATETIME NOT NULL,
+    synced_at DATETIME NOT NULL
+);
+CREATE INDEX idx_conversations_profile ON conversations(profile_id);
+
+CREATE TABLE messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    conversation_id INTEGER NOT NULL REFERENCES conversations(id),
+    sender TEXT NOT NULL,
+    sender_name TEXT NOT NULL DEFAULT '',
+    sent_at TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    synced_at DATETIME NOT NULL,
+    UNIQUE (conversation_id, sender, sent_at, text)
+);
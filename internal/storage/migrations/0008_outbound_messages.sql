@@ -0,0 +1,12 @@
+-- Exact text of every invite note and follow-up the bot sent.
+-- kind: 'note' (connection request; text '' if sent without a note) or 'followup'.
+CREATE TABLE outbound_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL REFERENCES profiles(id),
+    kind TEXT NOT NULL,
+    template TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    run_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX idx_outbound_messages_profile ON outbound_messages(profile_id);
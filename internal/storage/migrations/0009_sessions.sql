@@ -0,0 +1,11 @@
+-- Saved browser login per account (LinkedIn email), replacing cookies.json.
+-- cookies is the cookie jar JSON, NULL while it is kept in the encrypted secrets file instead.
+-- expires_at is the earliest expiry of the auth cookies (auth_cookie names it), NULL if none has one.
+CREATE TABLE sessions (
+    account TEXT PRIMARY KEY,
+    cookies TEXT,
+    cookie_count INTEGER NOT NULL DEFAULT 0,
+    auth_cookie TEXT NOT NULL DEFAULT '',
+    captured_at DATETIME NOT NULL,
+    expires_at DATETIME
+);
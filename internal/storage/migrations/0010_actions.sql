@@ -0,0 +1,38 @@
+-- Ledger of every outbound action against the site, appended as it happens. Rate limits count it.
+-- kind: 'profile_view', 'search_page', 'invite', 'message' or 'withdrawal'.
+-- count: new profiles a search page added; 1 for every other kind.
+CREATE TABLE actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    profile_id INTEGER REFERENCES profiles(id),
+    campaign_id INTEGER NOT NULL DEFAULT 1,
+    count INTEGER NOT NULL DEFAULT 1,
+    detail TEXT NOT NULL DEFAULT '',
+    run_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX idx_actions_kind_created ON actions(kind, created_at);
+CREATE INDEX idx_actions_profile ON actions(profile_id);
+
+-- Rebuild what history allows, so today's limits still hold after upgrading:
+-- invites from status events, follow-ups from the sent-text log, and collected profiles
+-- as one search page per keyword, result page and hour.
+INSERT INTO actions (kind, profile_id, campaign_id, detail, run_id, created_at)
+SELECT 'invite', e.profile_id, p.campaign_id, e.reason, e.run_id, e.created_at
+FROM profile_events e
+JOIN profiles p ON p.id = e.profile_id
+WHERE e.to_status = 'invited' AND e.from_status != 'invited'
+ORDER BY e.id;
+
+INSERT INTO actions (kind, profile_id, campaign_id, detail, run_id, created_at)
+SELECT 'message', o.profile_id, p.campaign_id, o.template, o.run_id, o.created_at
+FROM outbound_messages o
+JOIN profiles p ON p.id = o.profile_id
+WHERE o.kind = 'followup'
+ORDER BY o.id;
+
+INSERT INTO actions (kind, campaign_id, count, detail, created_at)
+SELECT 'search_page', campaign_id, COUNT(*), search_keyword, MIN(created_at)
+FROM profiles
+GROUP BY campaign_id, search_keyword, search_page, substr(created_at, 1, 13)
+ORDER BY MIN(created_at);
@@ -0,0 +1,12 @@
+-- Circuit breaker trips: account warning signals (or too many failures in a row) that stopped the bot.
+-- Until `until` passes or cleared_at is set, every run refuses to act.
+-- signal: 'invite_limit', 'checkpoint', 'restricted' or 'failures'.
+CREATE TABLE breaker_trips (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    signal TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    run_id TEXT NOT NULL DEFAULT '',
+    tripped_at DATETIME NOT NULL,
+    until DATETIME NOT NULL,
+    cleared_at DATETIME
+);
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// OutboundKind tells what kind of text was sent to a profile.
+type OutboundKind string
+
+const (
+	OutboundNote     OutboundKind = "note"     // Connection request, with or without a note
+	OutboundFollowup OutboundKind = "followup" // Follow-up message (one per sequence step)
+)
+
+// OutboundMessage is the exact text sent to a profile.
+type OutboundMessage struct {
+	ProfileURL string
+	Kind       OutboundKind
+	Template   string // Name of the template it was rendered from
+	Text       string // Empty for an invite sent without a note
+	RunID      string
+	CreatedAt  time.Time
+}
+
+// RecordOutbound stores text sent to the profile at url, tagged with the current run ID.
+func RecordOutbound(db *sql.DB, url string, kind OutboundKind, templateName, text string) error {
+	var id int64
+	err := db.QueryRow("SELECT id FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+        INSERT INTO outbound_messages (profile_id, kind, template, text, run_id, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `, id, string(kind), templateName, text, runID, time.Now())
+	return err
+}
+
+// GetOutboundMessages returns everything sent to a profile, oldest first.
+func GetOutboundMessages(db *sql.DB, url string) ([]OutboundMessage, error) {
+	rows, err := db.Query(`
+        SELECT p.url, o.kind, o.template, o.text, o.run_id, o.created_at
+        FROM outbound_messages o
+        JOIN profiles p ON p.id = o.profile_id
+        WHERE p.`+urlMatch+`
+        ORDER BY o.id ASC
+    `, urlArgs(url)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []OutboundMessage
+	for rows.Next() {
+		var m OutboundMessage
+		var kind string
+		if err := rows.Scan(&m.ProfileURL, &kind, &m.Template, &m.Text, &m.RunID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Kind = OutboundKind(kind)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecordOutbound(t *testing.T) {
+	db := openTestDB(t)
+	SetRunID("run-1")
+	t.Cleanup(func() { SetRunID("") })
+	const john = "https://www.linkedin.com/in/john-roe/"
+	addProfile(t, db, Profile{URL: janeURL})
+	addProfile(t, db, Profile{URL: john})
+
+	before := time.Now()
+	sent := []struct {
+		url      string
+		kind     OutboundKind
+		template string
+		text     string
+	}{
+		{janeURL, OutboundNote, "connect", "Hi Jane, I'd love to connect!"},
+		{john, OutboundNote, "connect", ""}, // Invite sent without a note
+		{"https://linkedin.com/in/Jane-Doe?trk=msg", OutboundFollowup, "followup_1", "Thanks for accepting!"},
+		{janeURL, OutboundFollowup, "followup_2", "Happy to swap notes."},
+	}
+	for _, s := range sent {
+		if err := RecordOutbound(db, s.url, s.kind, s.template, s.text); err != nil {
+			t.Fatalf("RecordOutbound(%s, %s): %v", s.url, s.kind, err)
+		}
+	}
+
+	got, err := GetOutboundMessages(db, janeURL)
+	if err != nil {
+		t.Fatalf("GetOutboundMessages: %v", err)
+	}
+	want := []OutboundMessage{
+		{Kind: OutboundNote, Template: "connect", Text: "Hi Jane, I'd love to connect!"},
+		{Kind: OutboundFollowup, Template: "followup_1", Text: "Thanks for accepting!"},
+		{Kind: OutboundFollowup, Template: "followup_2", Text: "Happy to swap notes."},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d messages sent to Jane, want %d: %+v", len(got), len(want), got)
+	}
+	for i, m := range got {
+		if m.Kind != want[i].Kind || m.Template != want[i].Template || m.Text != want[i].Text {
+			t.Errorf("message %d = %s/%s %q, want %s/%s %q", i, m.Kind, m.Template, m.Text, want[i].Kind, want[i].Template, want[i].Text)
+		}
+		if m.ProfileURL != CanonicalURL(janeURL) || m.RunID != "run-1" || m.CreatedAt.Before(before.Truncate(time.Second)) {
+			t.Errorf("message %d recorded for %s in run %q at %s", i, m.ProfileURL, m.RunID, m.CreatedAt)
+		}
+	}
+
+	johns, err := GetOutboundMessages(db, john)
+	if err != nil || len(johns) != 1 || johns[0].Text != "" {
+		t.Errorf("messages sent to John = %+v, %v; want the invite without a note", johns, err)
+	}
+}
+
+func TestRecordOutboundUnknownProfile(t *testing.T) {
+	db := openTestDB(t)
+	if err := RecordOutbound(db, janeURL, OutboundNote, "connect", "Hi"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("RecordOutbound error = %v, want ErrProfileNotFound", err)
+	}
+	if got, err := GetOutboundMessages(db, janeURL); err != nil || len(got) != 0 {
+		t.Errorf("GetOutboundMessages = %+v, %v; want nothing", got, err)
+	}
+}
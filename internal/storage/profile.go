@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProfileDetails are the identity fields scraped from search cards and profile pages.
+type ProfileDetails struct {
+	FullName  string
+	FirstName string
+	LastName  string
+	Headline  string
+	Company   string
+	Location  string
+}
+
+// Profile is a row of the profiles table.
+type Profile struct {
+	ID     int64
+	URL    string
+	Status ProfileStatus
+	ProfileDetails
+
+	// Where search found the profile
+	SearchKeyword string
+	SearchPage    int
+	SearchRank    int // 1-based position on the results page
+	CampaignID    int64
+
+	// Follow-up sequence progress
+	SequenceStep     int          // Steps sent so far
+	NextActionAt     sql.NullTime // When the next step is due
+	SequenceHaltedAt sql.NullTime // Set once the contact replied
+
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	RefreshedAt sql.NullTime // Last profile page visit, invalid if never visited
+}
+
+// profileColumns is the SELECT list matching scanProfile
+const profileColumns = `
+        id, url, status, full_name, first_name, last_name, headline, company, location,
+        search_keyword, search_page, search_rank, campaign_id,
+        sequence_step, next_action_at, sequence_halted_at, created_at, updated_at, refreshed_at
+`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanProfile reads one row selected with profileColumns
+func scanProfile(row rowScanner) (*Profile, error) {
+	var p Profile
+	var status string
+	err := row.Scan(&p.ID, &p.URL, &status,
+		&p.FullName, &p.FirstName, &p.LastName, &p.Headline, &p.Company, &p.Location,
+		&p.SearchKeyword, &p.SearchPage, &p.SearchRank, &p.CampaignID,
+		&p.SequenceStep, &p.NextActionAt, &p.SequenceHaltedAt,
+		&p.CreatedAt, &p.UpdatedAt, &p.RefreshedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.Status = ProfileStatus(status)
+	return &p, nil
+}
+
+// queryProfiles runs a query selecting profileColumns and collects the rows
+func queryProfiles(db *sql.DB, query string, args ...any) ([]Profile, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []Profile
+	for rows.Next() {
+		p, err := scanProfile(rows)
+		if err != nil {
+			continue
+		}
+		profiles = append(profiles, *p)
+	}
+	return profiles, rows.Err()
+}
+
+// GetProfile returns the stored profile for url, or sql.ErrNoRows if unknown.
+func GetProfile(db *sql.DB, url string) (*Profile, error) {
+	return scanProfile(db.QueryRow("SELECT "+profileColumns+" FROM profiles WHERE "+urlMatch, urlArgs(url)...))
+}
+
+// UpdateProfileDetails refreshes the scraped fields after a profile page visit.
+// Empty fields in details keep their stored value. It does not touch status or updated_at.
+func UpdateProfileDetails(db *sql.DB, url string, details ProfileDetails) error {
+	query := `
+        UPDATE profiles SET
+            full_name  = COALESCE(NULLIF(?, ''), full_name),
+            first_name = COALESCE(NULLIF(?, ''), first_name),
+            last_name  = COALESCE(NULLIF(?, ''), last_name),
+            headline   = COALESCE(NULLIF(?, ''), headline),
+            company    = COALESCE(NULLIF(?, ''), company),
+            location   = COALESCE(NULLIF(?, ''), location),
+            refreshed_at = ?
+        WHERE ` + urlMatch
+	args := []any{
+		details.FullName, details.FirstName, details.LastName,
+		details.Headline, details.Company, details.Location,
+		time.Now(),
+	}
+	_, err := db.Exec(query, append(args, urlArgs(url)...)...)
+	return err
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// GetProfilesDueForMessage retrieves the profiles message mode should act on now, most overdue first:
+//   - 'invited' profiles to check for acceptance (next_action_at unset), or whose first step is due
+//   - 'messaged' profiles whose next sequence step is due
+//
+// Profiles whose sequence was halted by a reply are never returned.
+func GetProfilesDueForMessage(db *sql.DB, limit int) ([]Profile, error) {
+	scope, scopeArgs := CampaignScope("campaign_id")
+	query := `
+        SELECT ` + profileColumns + `
+        FROM profiles
+        WHERE sequence_halted_at IS NULL
+          AND ((status = ? AND (next_action_at IS NULL OR next_action_at <= ?))
+            OR (status = ? AND next_action_at IS NOT NULL AND next_action_at <= ?))` + scope + `
+        ORDER BY COALESCE(next_action_at, updated_at) ASC
+        LIMIT ?
+    `
+	now := time.Now()
+	args := append([]any{string(StatusInvited), now, string(StatusMessaged), now}, scopeArgs...)
+	return queryProfiles(db, query, append(args, limit)...)
+}
+
+// ScheduleNextAction sets when the next sequence step of a profile is due.
+func ScheduleNextAction(db *sql.DB, url string, at time.Time) error {
+	_, err := db.Exec("UPDATE profiles SET next_action_at = ? WHERE "+urlMatch, append([]any{at}, urlArgs(url)...)...)
+	return err
+}
+
+// AdvanceSequence records that follow-up step number step (1-based) was sent: the profile
+// moves to 'messaged' and its next step is scheduled at next, or nothing if next is invalid.
+// The step is recorded in profile_events with reason.
+func AdvanceSequence(db *sql.DB, url string, step int, next sql.NullTime, reason string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	var current string
+	err = tx.QueryRow("SELECT id, status FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id, &current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return err
+	}
+	from := ProfileStatus(current)
+	if from != StatusMessaged && !CanTransition(from, StatusMessaged) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, StatusMessaged)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+        UPDATE profiles SET status = ?, sequence_step = ?, next_action_at = ?, updated_at = ?
+        WHERE id = ?
+    `, string(StatusMessaged), step, next, now, id)
+	if err != nil {
+		return err
+	}
+	if err := recordEvent(tx, id, from, StatusMessaged, reason, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger.Info("✅ Recorded follow-up step", logging.Profile(url), "step", step)
+	return nil
+}
+
+// HaltSequence stops the follow-up sequence of a profile for good, e.g. because the contact replied.
+// The status is left unchanged; the halt is recorded in profile_events with reason.
+func HaltSequence(db *sql.DB, url, reason string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	var current string
+	var halted sql.NullTime
+	err = tx.QueryRow("SELECT id, status, sequence_halted_at FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id, &current, &halted)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return err
+	}
+	if halted.Valid {
+		return nil
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE profiles SET sequence_halted_at = ?, next_action_at = NULL WHERE id = ?", now, id); err != nil {
+		return err
+	}
+	status := ProfileStatus(current)
+	if err := recordEvent(tx, id, status, status, reason, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger.Info("🛑 Halted follow-up sequence", logging.Profile(url), "reason", reason)
+	return nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Session is the saved browser login of one account.
+type Session struct {
+	Account     string
+	Cookies     []byte // Cookie jar JSON; nil while kept in the encrypted secrets file
+	CookieCount int
+	AuthCookie  string       // Auth cookie that expires first
+	CapturedAt  time.Time    // When the cookies were saved after a login
+	ExpiresAt   sql.NullTime // When AuthCookie expires, invalid if no auth cookie has an expiry
+}
+
+// SaveSession stores s as the account's session, replacing the previous one.
+func SaveSession(db *sql.DB, s *Session) error {
+	var cookies any
+	if s.Cookies != nil {
+		cookies = string(s.Cookies)
+	}
+	_, err := db.Exec(`
+        INSERT INTO sessions (account, cookies, cookie_count, auth_cookie, captured_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT(account) DO UPDATE SET
+            cookies = excluded.cookies,
+            cookie_count = excluded.cookie_count,
+            auth_cookie = excluded.auth_cookie,
+            captured_at = excluded.captured_at,
+            expires_at = excluded.expires_at
+    `, s.Account, cookies, s.CookieCount, s.AuthCookie, s.CapturedAt, s.ExpiresAt)
+	return err
+}
+
+// GetSession returns the account's saved session, or sql.ErrNoRows if there is none.
+func GetSession(db *sql.DB, account string) (*Session, error) {
+	var s Session
+	var cookies sql.NullString
+	err := db.QueryRow(`
+        SELECT account, cookies, cookie_count, auth_cookie, captured_at, expires_at
+        FROM sessions WHERE account = ?
+    `, account).Scan(&s.Account, &cookies, &s.CookieCount, &s.AuthCookie, &s.CapturedAt, &s.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if cookies.Valid {
+		s.Cookies = []byte(cookies.String)
+	}
+	return &s, nil
+}
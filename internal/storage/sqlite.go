@@ -2,46 +2,52 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
 )
 
-const (
-	dbFile      = "linkedin.db"
-	createTable = `
-        CREATE TABLE IF NOT EXISTS profiles (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            url TEXT UNIQUE NOT NULL,
-            status TEXT NOT NULL DEFAULT 'found',
-            created_at DATETIME NOT NULL,
-            updated_at DATETIME NOT NULL
-        );
-        CREATE INDEX IF NOT EXISTS idx_url ON profiles(url);
-        CREATE INDEX IF NOT EXISTS idx_status ON profiles(status);
-        CREATE INDEX IF NOT EXISTS idx_created_at ON profiles(created_at);
-        CREATE INDEX IF NOT EXISTS idx_updated_at ON profiles(updated_at);
-    `
-)
+const dbFile = "linkedin.db"
 
-// ProfileStats holds statistics about profiles in different lifecycle stages
+// ProfileStats holds statistics about profiles in different lifecycle stages.
+// ByStatus has an entry for every status in AllStatuses.
 type ProfileStats struct {
-	Total     int
-	Found     int
-	Invited   int
-	Connected int
-	Messaged  int
-	Pending   int
-	Premium   int
-	Failed    int
+	Total    int
+	ByStatus map[ProfileStatus]int
 }
 
-// InitDB initializes the SQLite database and creates necessary tables.
+// InitDB opens the SQLite database and applies any pending schema migrations.
 func InitDB() (*sql.DB, error) {
-	log.Printf("Initializing database: %s", dbFile)
+	db, err := OpenDB()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := Migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	version, _ := SchemaVersion(db)
+	logger.Info("Database initialized", "schema_version", version, "migrations_applied", len(applied))
+	return db, nil
+}
+
+// OpenDB opens the SQLite database without touching the schema.
+// The database can hold session cookies, so it is only readable by its owner.
+func OpenDB() (*sql.DB, error) {
+	logger.Debug("Opening database", "file", dbFile)
+
+	if err := restrictFiles(); err != nil {
+		return nil, fmt.Errorf("failed to restrict database permissions: %w", err)
+	}
 
 	db, err := sql.Open("sqlite", dbFile)
 	if err != nil {
@@ -53,48 +59,105 @@ func InitDB() (*sql.DB, error) {
 	}
 
 	if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
-		log.Printf("Warning: Failed to enable WAL mode: %v", err)
+		logger.Warn("Failed to enable WAL mode", logging.Err(err))
 	} else {
-		log.Println("WAL mode enabled for better concurrency")
-	}
-
-	if _, err := db.Exec(createTable); err != nil {
-		return nil, err
+		logger.Debug("WAL mode enabled for better concurrency")
 	}
 
-	log.Println("Database initialized successfully")
 	return db, nil
 }
 
-// AddProfile inserts a new profile URL into the database.
+// restrictFiles creates the database file if needed and sets it, and the WAL and shared-memory files
+// left next to it, to 0600. SQLite creates later WAL and shared-memory files with the database's mode.
+func restrictFiles() error {
+	f, err := os.OpenFile(dbFile, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	for _, name := range []string{dbFile, dbFile + "-wal", dbFile + "-shm"} {
+		if err := os.Chmod(name, 0600); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddProfile inserts a new profile (URL, scraped details and search origin) into the database
+// and records its initial 'found' event. p.Status and timestamps are ignored.
+// The URL is canonicalized first, so variants of an existing profile count as duplicates.
+// Profiles on the do-not-contact list are stored as 'suppressed' so they are never queued.
+// The profile joins the campaign in use (see UseCampaign), or the default campaign.
 // RETURNS: (bool, error) -> true if added, false if duplicate/ignored
-func AddProfile(db *sql.DB, url string) (bool, error) {
+func AddProfile(db *sql.DB, p Profile) (bool, error) {
 	query := `
-        INSERT OR IGNORE INTO profiles (url, status, created_at, updated_at)
-        VALUES (?, 'found', ?, ?)
+        INSERT OR IGNORE INTO profiles (
+            url, status, full_name, first_name, last_name, headline, company, location,
+            search_keyword, search_page, search_rank, campaign_id, created_at, updated_at
+        )
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
+	if IsProfileVisited(db, p.URL) {
+		return false, nil
+	}
+	p.URL = CanonicalURL(p.URL)
+
+	status, reason := StatusFound, "search"
+	suppressed, err := CheckSuppressed(db, p)
+	if err != nil {
+		return false, err
+	}
+	if suppressed != nil {
+		logger.Info("🚫 Profile is on the suppression list", logging.Profile(p.URL), "entry", suppressed.String())
+		status, reason = StatusSuppressed, fmt.Sprintf("search: on suppression list (%s)", suppressed)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	campaign := campaignID
+	if campaign == 0 {
+		campaign = defaultCampaignID
+	}
+
 	now := time.Now()
-	result, err := db.Exec(query, url, now, now)
+	result, err := tx.Exec(query, p.URL, string(status),
+		p.FullName, p.FirstName, p.LastName, p.Headline, p.Company, p.Location,
+		p.SearchKeyword, p.SearchPage, p.SearchRank, campaign, now, now)
 	if err != nil {
-		log.Printf("Error adding profile %s: %v", url, err)
+		logger.Error("Failed to add profile", logging.Profile(p.URL), logging.Err(err))
 		return false, err
 	}
 
 	// Check if row was actually inserted
 	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected > 0 {
-		// log.Printf("✅ Added new profile: %s", url) // Optional: uncomment for verbose logs
-		return true, nil
+	if rowsAffected == 0 {
+		return false, nil
 	}
 
-	return false, nil
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	if err := recordEvent(tx, id, "", status, reason, now); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	logger.Debug("✅ Added new profile", logging.Profile(p.URL))
+	return true, nil
 }
 
-// IsProfileVisited checks if a profile URL exists in the database.
+// IsProfileVisited checks if a profile URL (or any variant with the same canonical form) exists in the database.
 func IsProfileVisited(db *sql.DB, url string) bool {
 	var count int
-	query := "SELECT COUNT(*) FROM profiles WHERE url = ?"
-	err := db.QueryRow(query, url).Scan(&count)
+	query := "SELECT COUNT(*) FROM profiles WHERE " + urlMatch
+	err := db.QueryRow(query, urlArgs(url)...).Scan(&count)
 	if err != nil {
 		return false
 	}
@@ -102,112 +165,99 @@ func IsProfileVisited(db *sql.DB, url string) bool {
 }
 
 // GetProfilesToInvite retrieves profiles with status 'found' that need connection invites.
-func GetProfilesToInvite(db *sql.DB, limit int) ([]string, error) {
+// Profiles matching the do-not-contact list are marked 'suppressed' first, so they are never returned.
+// Like every profile query it only sees the campaign in use.
+func GetProfilesToInvite(db *sql.DB, limit int) ([]Profile, error) {
+	if _, err := ApplySuppressions(db); err != nil {
+		return nil, err
+	}
+
+	scope, scopeArgs := CampaignScope("campaign_id")
 	query := `
-        SELECT url 
+        SELECT ` + profileColumns + `
         FROM profiles 
-        WHERE status = 'found' 
+        WHERE status = ?` + scope + `
         ORDER BY created_at ASC 
         LIMIT ?
     `
-	rows, err := db.Query(query, limit)
+	args := append(append([]any{string(StatusFound)}, scopeArgs...), limit)
+	profiles, err := queryProfiles(db, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var urls []string
-	for rows.Next() {
-		var url string
-		if err := rows.Scan(&url); err != nil {
-			continue
-		}
-		urls = append(urls, url)
-	}
-
-	log.Printf("Found %d profiles ready for invitation", len(urls))
-	return urls, nil
-}
-
-// UpdateStatus updates the status of a profile.
-func UpdateStatus(db *sql.DB, url string, newStatus string) error {
-	query := `
-        UPDATE profiles
-        SET status = ?, updated_at = CURRENT_TIMESTAMP
-        WHERE url = ?
-    `
-	result, err := db.Exec(query, newStatus, url)
-	if err != nil {
-		return err
-	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected > 0 {
-		log.Printf("✅ Updated %s status to '%s'", url, newStatus)
-	}
-
-	return nil
+	logger.Info("Found profiles ready for invitation", "count", len(profiles))
+	return profiles, nil
 }
 
 // GetProfilesByStatus retrieves profiles with a specific status.
-func GetProfilesByStatus(db *sql.DB, status string, limit int) ([]string, error) {
+func GetProfilesByStatus(db *sql.DB, status ProfileStatus, limit int) ([]Profile, error) {
+	scope, scopeArgs := CampaignScope("campaign_id")
 	query := `
-        SELECT url 
+        SELECT ` + profileColumns + `
         FROM profiles 
-        WHERE status = ? 
+        WHERE status = ?` + scope + `
         ORDER BY updated_at ASC 
         LIMIT ?
     `
-	rows, err := db.Query(query, status, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var urls []string
-	for rows.Next() {
-		var url string
-		if err := rows.Scan(&url); err != nil {
-			continue
-		}
-		urls = append(urls, url)
-	}
+	return queryProfiles(db, query, append(append([]any{string(status)}, scopeArgs...), limit)...)
+}
 
-	return urls, nil
+// GetProfilesForReplyCheck retrieves messaged profiles whose chat should be checked for replies,
+// least recently visited first (never visited profiles come first).
+func GetProfilesForReplyCheck(db *sql.DB, limit int) ([]Profile, error) {
+	scope, scopeArgs := CampaignScope("campaign_id")
+	query := `
+        SELECT ` + profileColumns + `
+        FROM profiles
+        WHERE status = ?` + scope + `
+        ORDER BY refreshed_at ASC, updated_at ASC
+        LIMIT ?
+    `
+	return queryProfiles(db, query, append(append([]any{string(StatusMessaged)}, scopeArgs...), limit)...)
 }
 
 // GetStats returns comprehensive statistics about profiles in the database.
+// Each profile counts under the status of its latest profile_events entry, the audit trail every
+// status change writes; profiles stored before events were recorded fall back to their status column.
 func GetStats(db *sql.DB) (*ProfileStats, error) {
-	stats := &ProfileStats{}
-
-	// Get total count
-	if err := db.QueryRow("SELECT COUNT(*) FROM profiles").Scan(&stats.Total); err != nil {
-		return nil, err
+	stats := &ProfileStats{ByStatus: make(map[ProfileStatus]int, len(AllStatuses))}
+	for _, status := range AllStatuses {
+		stats.ByStatus[status] = 0
 	}
 
-	// Get count by status
-	statusCounts := map[string]*int{
-		"found":        &stats.Found,
-		"invited":      &stats.Invited,
-		"connected":    &stats.Connected,
-		"messaged":     &stats.Messaged,
-		"pending":      &stats.Pending,
-		"premium_only": &stats.Premium,
-		"failed":       &stats.Failed,
+	scope, scopeArgs := CampaignScope("p.campaign_id")
+	rows, err := db.Query(`
+        SELECT COALESCE(e.to_status, p.status) AS current, COUNT(*)
+        FROM profiles p
+        LEFT JOIN profile_events e ON e.id = (SELECT MAX(id) FROM profile_events WHERE profile_id = p.id)
+        WHERE 1 = 1`+scope+`
+        GROUP BY current
+    `, scopeArgs...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	for status, countPtr := range statusCounts {
-		_ = db.QueryRow("SELECT COUNT(*) FROM profiles WHERE status = ?", status).Scan(countPtr)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		stats.ByStatus[ProfileStatus(status)] = count
+		stats.Total += count
 	}
 
-	return stats, nil
+	return stats, rows.Err()
 }
 
 // Count returns the number of profiles with a specific status.
-func Count(db *sql.DB, status string) (int, error) {
+func Count(db *sql.DB, status ProfileStatus) (int, error) {
 	var count int
-	query := "SELECT COUNT(*) FROM profiles WHERE status = ?"
-	err := db.QueryRow(query, status).Scan(&count)
+	scope, scopeArgs := CampaignScope("campaign_id")
+	query := "SELECT COUNT(*) FROM profiles WHERE status = ?" + scope
+	err := db.QueryRow(query, append([]any{string(status)}, scopeArgs...)...).Scan(&count)
 	return count, err
 }
 
@@ -216,7 +266,8 @@ func GetProfilesCreatedToday(db *sql.DB) (int, error) {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM profiles WHERE created_at >= ?", startOfDay).Scan(&count)
+	scope, scopeArgs := CampaignScope("campaign_id")
+	err := db.QueryRow("SELECT COUNT(*) FROM profiles WHERE created_at >= ?"+scope, append([]any{startOfDay}, scopeArgs...)...).Scan(&count)
 	return count, err
 }
 
@@ -225,7 +276,8 @@ func GetProfilesUpdatedToday(db *sql.DB) (int, error) {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM profiles WHERE updated_at >= ?", startOfDay).Scan(&count)
+	scope, scopeArgs := CampaignScope("campaign_id")
+	err := db.QueryRow("SELECT COUNT(*) FROM profiles WHERE updated_at >= ?"+scope, append([]any{startOfDay}, scopeArgs...)...).Scan(&count)
 	return count, err
 }
 
@@ -240,13 +292,13 @@ func PrintStats(db *sql.DB) error {
 	fmt.Println("📊 LINKEDIN AUTOMATION DATABASE STATISTICS")
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Printf("Total Profiles:          %d\n", stats.Total)
-	fmt.Printf("├─ Found (ready):        %d\n", stats.Found)
-	fmt.Printf("├─ Invited (pending):    %d\n", stats.Invited)
-	fmt.Printf("├─ Connected:            %d\n", stats.Connected)
-	fmt.Printf("├─ Messaged:             %d\n", stats.Messaged)
-	fmt.Printf("├─ Pending Review:       %d\n", stats.Pending)
-	fmt.Printf("├─ Premium Only:         %d\n", stats.Premium)
-	fmt.Printf("└─ Failed (retry):       %d\n", stats.Failed)
+	for i, status := range AllStatuses {
+		branch := "├─"
+		if i == len(AllStatuses)-1 {
+			branch = "└─"
+		}
+		fmt.Printf("%s %-21s %d\n", branch, string(status)+":", stats.ByStatus[status])
+	}
 	fmt.Println(strings.Repeat("=", 50) + "\n")
 
 	return nil
@@ -255,4 +307,4 @@ func PrintStats(db *sql.DB) error {
 // CloseDB closes the database connection gracefully.
 func CloseDB(db *sql.DB) error {
 	return db.Close()
-}
\ No newline at end of file
+}
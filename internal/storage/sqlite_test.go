@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+)
+
+// openTestDB creates a fresh database in a temporary working directory.
+// The campaign scope is reset when the test ends.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		campaignID = 0
+	})
+	return db
+}
+
+// addProfile stores a profile found by search, failing the test on error
+func addProfile(t *testing.T, db *sql.DB, p Profile) {
+	t.Helper()
+	if _, err := AddProfile(db, p); err != nil {
+		t.Fatalf("AddProfile(%s): %v", p.URL, err)
+	}
+}
+
+// statusOf returns the stored status of the profile at url
+func statusOf(t *testing.T, db *sql.DB, url string) ProfileStatus {
+	t.Helper()
+	var status ProfileStatus
+	if err := db.QueryRow("SELECT status FROM profiles WHERE url = ?", url).Scan(&status); err != nil {
+		t.Fatalf("status of %s: %v", url, err)
+	}
+	return status
+}
+
+func TestInitDBRestrictsPermissions(t *testing.T) {
+	db := openTestDB(t)
+	if err := SaveSession(db, &Session{Account: "a@example.com", Cookies: []byte("[]")}); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+
+	for _, name := range []string{dbFile, dbFile + "-wal", dbFile + "-shm"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("stat %s: %v", name, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s has mode %o, want 600", name, perm)
+		}
+	}
+}
+
+func TestAddProfileDedupesVariants(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		url   string
+		added bool
+	}{
+		{"https://www.linkedin.com/in/jane-doe/", true},
+		{"https://linkedin.com/in/Jane-Doe", false},
+		{"https://www.linkedin.com/in/jane-doe/en?trk=search", false},
+		{"https://www.linkedin.com/in/john-doe", true},
+	}
+	for _, tt := range tests {
+		added, err := AddProfile(db, Profile{URL: tt.url})
+		if err != nil {
+			t.Fatalf("AddProfile(%s): %v", tt.url, err)
+		}
+		if added != tt.added {
+			t.Errorf("AddProfile(%s) = %v, want %v", tt.url, added, tt.added)
+		}
+	}
+
+	stats, err := GetStats(db)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.Total != 2 {
+		t.Errorf("stored %d profiles, want 2", stats.Total)
+	}
+}
+
+func TestGetStatsMatchesLatestEvents(t *testing.T) {
+	db := openTestDB(t)
+	const base = "https://www.linkedin.com/in/"
+	steps := map[string]func(url string) error{
+		"found": func(url string) error { return nil },
+		"messaged": func(url string) error {
+			if err := UpdateStatus(db, url, StatusInvited, "test"); err != nil {
+				return err
+			}
+			if err := AdvanceSequence(db, url, 1, sql.NullTime{}, "test: step 1"); err != nil {
+				return err
+			}
+			return HaltSequence(db, url, "test: halted") // Records an event without a status change
+		},
+		"replied": func(url string) error {
+			if err := UpdateStatus(db, url, StatusInvited, "test"); err != nil {
+				return err
+			}
+			_, err := MarkReplied(db, url, "test: replied")
+			return err
+		},
+		"retried": func(url string) error {
+			if err := UpdateStatus(db, url, StatusFailed, "test"); err != nil {
+				return err
+			}
+			return UpdateStatus(db, url, StatusFound, "test: retry")
+		},
+		"opted-out": func(url string) error { return UpdateStatus(db, url, StatusOptedOut, "test") },
+	}
+	for slug, step := range steps {
+		addProfile(t, db, Profile{URL: base + slug})
+		if err := step(base + slug); err != nil {
+			t.Fatalf("%s: %v", slug, err)
+		}
+	}
+	// A row stored before profile_events existed has no history
+	now := time.Now()
+	if _, err := db.Exec("INSERT INTO profiles (url, status, created_at, updated_at) VALUES (?, ?, ?, ?)", base+"legacy", StatusPending, now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := GetStats(db)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	want := map[ProfileStatus]int{StatusFound: 2, StatusMessaged: 1, StatusReplied: 1, StatusOptedOut: 1, StatusPending: 1}
+	for _, status := range AllStatuses {
+		if stats.ByStatus[status] != want[status] {
+			t.Errorf("%s: %d profiles, want %d", status, stats.ByStatus[status], want[status])
+		}
+	}
+	if stats.Total != 6 {
+		t.Errorf("total %d, want 6", stats.Total)
+	}
+
+	// The counts agree with the status column profile by profile
+	column := make(map[ProfileStatus]int)
+	for slug := range steps {
+		column[statusOf(t, db, base+slug)]++
+	}
+	column[statusOf(t, db, base+"legacy")]++
+	for status, n := range column {
+		if stats.ByStatus[status] != n {
+			t.Errorf("%s: GetStats counts %d, the status column %d", status, stats.ByStatus[status], n)
+		}
+	}
+}
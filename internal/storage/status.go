@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SNKT2024/linkedin-automation/internal/logging"
+)
+
+// ProfileStatus is the lifecycle stage of a profile.
+type ProfileStatus string
+
+const (
+	StatusFound            ProfileStatus = "found"             // Collected by search, ready to invite
+	StatusInvited          ProfileStatus = "invited"           // Connection request sent by the bot
+	StatusPending          ProfileStatus = "pending"           // An invite is outstanding (sent earlier or not yet accepted)
+	StatusAlreadyConnected ProfileStatus = "already_connected" // Was already a connection before we invited
+	StatusPremiumOnly      ProfileStatus = "premium_only"      // Only reachable through InMail
+	StatusMessaged         ProfileStatus = "messaged"          // Follow-up message sent
+	StatusReplied          ProfileStatus = "replied"           // The contact answered in the chat; no more automated follow-ups
+	StatusFailed           ProfileStatus = "failed"            // Connect attempt failed for an unexplained reason
+	StatusSuppressed       ProfileStatus = "suppressed"        // On the do-not-contact list, never invited or messaged
+	StatusOptedOut         ProfileStatus = "opted_out"         // Replied asking us to stop, never contacted again
+)
+
+// AllStatuses lists every status in lifecycle order.
+var AllStatuses = []ProfileStatus{
+	StatusFound,
+	StatusInvited,
+	StatusPending,
+	StatusAlreadyConnected,
+	StatusPremiumOnly,
+	StatusMessaged,
+	StatusReplied,
+	StatusFailed,
+	StatusSuppressed,
+	StatusOptedOut,
+}
+
+// transitions is the allowed-transition table. Statuses without an entry are terminal.
+// Every other status may move to 'suppressed' or 'opted_out', which are both terminal.
+var transitions = map[ProfileStatus][]ProfileStatus{
+	StatusFound:            {StatusInvited, StatusPending, StatusAlreadyConnected, StatusPremiumOnly, StatusFailed, StatusSuppressed, StatusOptedOut},
+	StatusFailed:           {StatusFound, StatusInvited, StatusPending, StatusAlreadyConnected, StatusPremiumOnly, StatusSuppressed, StatusOptedOut},
+	StatusInvited:          {StatusPending, StatusPremiumOnly, StatusMessaged, StatusReplied, StatusSuppressed, StatusOptedOut},
+	StatusPending:          {StatusInvited, StatusPremiumOnly, StatusMessaged, StatusReplied, StatusSuppressed, StatusOptedOut},
+	StatusAlreadyConnected: {StatusMessaged, StatusReplied, StatusSuppressed, StatusOptedOut},
+	StatusPremiumOnly:      {StatusMessaged, StatusReplied, StatusSuppressed, StatusOptedOut},
+	StatusMessaged:         {StatusReplied, StatusSuppressed, StatusOptedOut},
+	StatusReplied:          {StatusSuppressed, StatusOptedOut},
+}
+
+// statusRank orders statuses by how far along the lifecycle a profile is.
+// DedupeProfiles keeps the highest-ranked status when merging duplicates,
+// so a suppressed or opted-out duplicate always wins.
+var statusRank = map[ProfileStatus]int{
+	StatusFound:            0,
+	StatusFailed:           1,
+	StatusPremiumOnly:      2,
+	StatusPending:          3,
+	StatusInvited:          4,
+	StatusAlreadyConnected: 5,
+	StatusMessaged:         6,
+	StatusReplied:          7,
+	StatusOptedOut:         8,
+	StatusSuppressed:       9,
+}
+
+// ErrInvalidTransition is returned when a status change is not in the transition table.
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrProfileNotFound is returned when updating a profile that is not in the database.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// runID tags every recorded event with the run that produced it.
+var runID string
+
+// SetRunID sets the run ID recorded with every subsequent profile event.
+func SetRunID(id string) {
+	runID = id
+}
+
+// Valid reports whether s is a known status.
+func (s ProfileStatus) Valid() bool {
+	for _, known := range AllStatuses {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether a profile may move from one status to another.
+func CanTransition(from, to ProfileStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileEvent is one recorded status transition.
+type ProfileEvent struct {
+	ProfileURL string
+	FromStatus ProfileStatus // Empty for the initial insert
+	ToStatus   ProfileStatus
+	Reason     string
+	RunID      string
+	CreatedAt  time.Time
+}
+
+// recordEvent appends a transition to profile_events inside tx
+func recordEvent(tx *sql.Tx, profileID int64, from, to ProfileStatus, reason string, at time.Time) error {
+	_, err := tx.Exec(`
+        INSERT INTO profile_events (profile_id, from_status, to_status, reason, run_id, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `, profileID, string(from), string(to), reason, runID, at)
+	return err
+}
+
+// GetProfileEvents returns the transition history of a profile, oldest first.
+func GetProfileEvents(db *sql.DB, url string) ([]ProfileEvent, error) {
+	rows, err := db.Query(`
+        SELECT p.url, e.from_status, e.to_status, e.reason, e.run_id, e.created_at
+        FROM profile_events e
+        JOIN profiles p ON p.id = e.profile_id
+        WHERE p.`+urlMatch+`
+        ORDER BY e.id ASC
+    `, urlArgs(url)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []ProfileEvent
+	for rows.Next() {
+		var e ProfileEvent
+		var from, to string
+		if err := rows.Scan(&e.ProfileURL, &from, &to, &e.Reason, &e.RunID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.FromStatus, e.ToStatus = ProfileStatus(from), ProfileStatus(to)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// UpdateStatus moves a profile to newStatus if the transition table allows it
+// and records the transition with reason in profile_events. url is matched by canonical form.
+// Setting the current status again is a no-op.
+func UpdateStatus(db *sql.DB, url string, newStatus ProfileStatus, reason string) error {
+	if !newStatus.Valid() {
+		return fmt.Errorf("unknown status %q", newStatus)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	var current string
+	err = tx.QueryRow("SELECT id, status FROM profiles WHERE "+urlMatch, urlArgs(url)...).Scan(&id, &current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, url)
+	}
+	if err != nil {
+		return err
+	}
+
+	from := ProfileStatus(current)
+	if from == newStatus {
+		return nil
+	}
+	if !CanTransition(from, newStatus) {
+		logger.Warn("⚠️ Rejected status change", logging.Profile(url), "from", from, "to", newStatus)
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, newStatus)
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE profiles SET status = ?, updated_at = ? WHERE id = ?", string(newStatus), now, id); err != nil {
+		return err
+	}
+	if err := recordEvent(tx, id, from, newStatus, reason, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger.Info("✅ Updated status", logging.Profile(url), "from", from, "status", newStatus)
+	return nil
+}
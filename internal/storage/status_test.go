@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to ProfileStatus
+		want     bool
+	}{
+		{StatusFound, StatusInvited, true},
+		{StatusFound, StatusMessaged, false},
+		{StatusFailed, StatusFound, true},
+		{StatusInvited, StatusPending, true},
+		{StatusPending, StatusInvited, true},
+		{StatusInvited, StatusFound, false},
+		{StatusMessaged, StatusReplied, true},
+		{StatusMessaged, StatusInvited, false},
+		{StatusReplied, StatusMessaged, false},
+		{StatusReplied, StatusOptedOut, true},
+		{StatusSuppressed, StatusFound, false},
+		{StatusOptedOut, StatusSuppressed, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestEveryStatusCanBeSuppressed(t *testing.T) {
+	for _, s := range AllStatuses {
+		if s == StatusSuppressed || s == StatusOptedOut {
+			continue
+		}
+		for _, to := range []ProfileStatus{StatusSuppressed, StatusOptedOut} {
+			if !CanTransition(s, to) {
+				t.Errorf("%s cannot move to %s", s, to)
+			}
+		}
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	db := openTestDB(t)
+	const url = "https://www.linkedin.com/in/jane-doe"
+	addProfile(t, db, Profile{URL: url})
+
+	steps := []struct {
+		to      ProfileStatus
+		wantErr error
+		want    ProfileStatus
+	}{
+		{StatusInvited, nil, StatusInvited},
+		{StatusInvited, nil, StatusInvited}, // Same status again is a no-op
+		{StatusFound, ErrInvalidTransition, StatusInvited},
+		{StatusMessaged, nil, StatusMessaged},
+		{StatusPending, ErrInvalidTransition, StatusMessaged},
+		{StatusOptedOut, nil, StatusOptedOut},
+		{StatusReplied, ErrInvalidTransition, StatusOptedOut},
+	}
+	for _, step := range steps {
+		err := UpdateStatus(db, url+"/?trk=x", step.to, "test")
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("UpdateStatus(%s) error = %v, want %v", step.to, err, step.wantErr)
+		}
+		if got := statusOf(t, db, url); got != step.want {
+			t.Fatalf("after UpdateStatus(%s) status = %s, want %s", step.to, got, step.want)
+		}
+	}
+
+	events, err := GetProfileEvents(db, url)
+	if err != nil {
+		t.Fatalf("GetProfileEvents: %v", err)
+	}
+	// found, invited, messaged, opted_out: refused and repeated changes are not recorded
+	if len(events) != 4 {
+		t.Errorf("recorded %d events, want 4", len(events))
+	}
+}
+
+func TestUpdateStatusErrors(t *testing.T) {
+	db := openTestDB(t)
+
+	if err := UpdateStatus(db, "https://www.linkedin.com/in/nobody", StatusInvited, "test"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("unknown profile: error = %v, want ErrProfileNotFound", err)
+	}
+	addProfile(t, db, Profile{URL: "https://www.linkedin.com/in/jane-doe"})
+	if err := UpdateStatus(db, "https://www.linkedin.com/in/jane-doe", "archived", "test"); err == nil {
+		t.Error("unknown status: no error")
+	}
+}
@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// SuppressionKind is the profile field a suppression entry is matched against.
+type SuppressionKind string
+
+const (
+	SuppressURL     SuppressionKind = "url"     // Matches the canonical profile URL
+	SuppressName    SuppressionKind = "name"    // Matches the full name, ignoring case and extra spaces
+	SuppressCompany SuppressionKind = "company" // Matches the current company, ignoring case and extra spaces
+)
+
+// Suppression is one do-not-contact entry.
+type Suppression struct {
+	Kind   SuppressionKind
+	Value  string
+	Reason string
+}
+
+func (s Suppression) String() string {
+	return fmt.Sprintf("%s=%q", s.Kind, s.Value)
+}
+
+// normalizeSuppression returns value in the form it is stored and compared in
+func normalizeSuppression(kind SuppressionKind, value string) string {
+	if kind == SuppressURL {
+		return CanonicalURL(value)
+	}
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// AddSuppression adds an entry to the do-not-contact list.
+// RETURNS: (bool, error) -> true if added, false if it was already listed
+func AddSuppression(db *sql.DB, s Suppression) (bool, error) {
+	switch s.Kind {
+	case SuppressURL, SuppressName, SuppressCompany:
+	default:
+		return false, fmt.Errorf("unknown suppression kind %q", s.Kind)
+	}
+	value := normalizeSuppression(s.Kind, s.Value)
+	if value == "" {
+		return false, errors.New("empty suppression value")
+	}
+
+	result, err := db.Exec(`
+        INSERT OR IGNORE INTO suppression (kind, value, reason, created_at)
+        VALUES (?, ?, ?, ?)
+    `, string(s.Kind), value, s.Reason, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// ImportSuppressionCSV adds every entry of a CSV file to the do-not-contact list.
+// The first row may be a header naming the columns url, name, company and reason
+// (any order, any subset); without a header the columns are url,name,company,reason.
+// Every non-empty url/name/company cell becomes its own entry; a url that is not a profile
+// on the configured site could never match and is skipped with a warning.
+// RETURNS: (added, total, error) -> new entries and entries read (including already listed ones)
+func ImportSuppressionCSV(db *sql.DB, path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	columns := []string{"url", "name", "company", "reason"}
+	added, total, line := 0, 0, 0
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return added, total, fmt.Errorf("%s: %w", path, err)
+		}
+		line++
+
+		if line == 1 && isSuppressionHeader(record) {
+			columns = make([]string, len(record))
+			for i, name := range record {
+				columns[i] = strings.ToLower(strings.TrimSpace(name))
+			}
+			continue
+		}
+
+		var reason string
+		var entries []Suppression
+		for i, cell := range record {
+			if i >= len(columns) || strings.TrimSpace(cell) == "" {
+				continue
+			}
+			switch columns[i] {
+			case "reason":
+				reason = strings.TrimSpace(cell)
+			case "url":
+				if !isProfileURL(CanonicalURL(cell)) {
+					logger.Warn("⚠️ Skipped suppression entry: not a profile URL", "file", path, "line", line, "url", cell)
+					continue
+				}
+				entries = append(entries, Suppression{Kind: SuppressURL, Value: cell})
+			case "name", "company":
+				entries = append(entries, Suppression{Kind: SuppressionKind(columns[i]), Value: cell})
+			}
+		}
+
+		for _, s := range entries {
+			s.Reason = reason
+			ok, err := AddSuppression(db, s)
+			if err != nil {
+				return added, total, fmt.Errorf("%s line %d: %w", path, line, err)
+			}
+			total++
+			if ok {
+				added++
+			}
+		}
+	}
+	return added, total, nil
+}
+
+// isSuppressionHeader reports whether a CSV row names columns rather than holding data
+func isSuppressionHeader(record []string) bool {
+	for _, cell := range record {
+		switch strings.ToLower(strings.TrimSpace(cell)) {
+		case "url", "name", "company", "reason":
+			return true
+		}
+	}
+	return false
+}
+
+// GetSuppressions returns the whole do-not-contact list, oldest first.
+func GetSuppressions(db *sql.DB) ([]Suppression, error) {
+	rows, err := db.Query("SELECT kind, value, reason FROM suppression ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Suppression
+	for rows.Next() {
+		var s Suppression
+		var kind string
+		if err := rows.Scan(&kind, &s.Value, &s.Reason); err != nil {
+			return nil, err
+		}
+		s.Kind = SuppressionKind(kind)
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// CheckSuppressed returns the entry matching p (by URL, full name or company),
+// or nil if p may be contacted.
+func CheckSuppressed(db *sql.DB, p Profile) (*Suppression, error) {
+	var s Suppression
+	var kind string
+	err := db.QueryRow(`
+        SELECT kind, value, reason FROM suppression
+        WHERE (kind = 'url' AND value IN (?, ?))
+           OR (kind = 'name' AND value = ?)
+           OR (kind = 'company' AND value = ?)
+        ORDER BY id ASC
+        LIMIT 1
+    `, CanonicalURL(p.URL), strings.TrimSpace(p.URL),
+		normalizeSuppression(SuppressName, p.FullName),
+		normalizeSuppression(SuppressCompany, p.Company)).Scan(&kind, &s.Value, &s.Reason)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Kind = SuppressionKind(kind)
+	return &s, nil
+}
+
+// SuppressProfile moves a profile to 'suppressed' because it matches s.
+func SuppressProfile(db *sql.DB, url string, s *Suppression, source string) error {
+	reason := fmt.Sprintf("%s: on suppression list (%s)", source, s)
+	if s.Reason != "" {
+		reason += ": " + s.Reason
+	}
+	return UpdateStatus(db, url, StatusSuppressed, reason)
+}
+
+// MarkOptedOut moves a profile to the terminal 'opted_out' status and adds its URL to the
+// do-not-contact list, so the person stays excluded even if found again under another URL row.
+func MarkOptedOut(db *sql.DB, url, reason string) error {
+	if err := UpdateStatus(db, url, StatusOptedOut, reason); err != nil {
+		return err
+	}
+	_, err := AddSuppression(db, Suppression{Kind: SuppressURL, Value: url, Reason: "opted out: " + reason})
+	return err
+}
+
+// ApplySuppressions marks every profile that matches the do-not-contact list as 'suppressed'.
+// Profiles already in a terminal status (suppressed, opted_out) are left alone.
+// RETURNS: number of profiles newly suppressed
+func ApplySuppressions(db *sql.DB) (int, error) {
+	profiles, err := queryProfiles(db, "SELECT "+profileColumns+" FROM profiles WHERE status != ?", string(StatusSuppressed))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, p := range profiles {
+		if !CanTransition(p.Status, StatusSuppressed) {
+			continue
+		}
+		s, err := CheckSuppressed(db, p)
+		if err != nil {
+			return count, err
+		}
+		if s == nil {
+			continue
+		}
+		if err := SuppressProfile(db, p.URL, s, "suppression"); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if count > 0 {
+		logger.Info("🚫 Suppressed profiles on the do-not-contact list", "count", count)
+	}
+	return count, nil
+}
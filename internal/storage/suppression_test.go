@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeCSV writes content to a CSV file in a temporary directory and returns its path
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "suppression.csv")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportSuppressionCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		csv        string
+		wantAdded  int
+		wantTotal  int
+		wantStored []Suppression
+	}{
+		{
+			name:      "header in any order",
+			csv:       "reason,company,url\nclient,,https://www.linkedin.com/in/Some-Client/\ncoworkers,Acme  Corp,\n",
+			wantAdded: 2, wantTotal: 2,
+			wantStored: []Suppression{
+				{Kind: SuppressURL, Value: "https://www.linkedin.com/in/some-client", Reason: "client"},
+				{Kind: SuppressCompany, Value: "acme corp", Reason: "coworkers"},
+			},
+		},
+		{
+			name:      "no header uses url,name,company,reason",
+			csv:       "https://linkedin.com/in/jane-doe,Jane Doe,,asked us to stop\n",
+			wantAdded: 2, wantTotal: 2,
+			wantStored: []Suppression{
+				{Kind: SuppressURL, Value: "https://www.linkedin.com/in/jane-doe", Reason: "asked us to stop"},
+				{Kind: SuppressName, Value: "jane doe", Reason: "asked us to stop"},
+			},
+		},
+		{
+			name:      "blank lines, blank cells and comments",
+			csv:       "url,name,company,reason\n\n# internal note\n, ,,\n,,Acme,\n   \n",
+			wantAdded: 1, wantTotal: 1,
+			wantStored: []Suppression{{Kind: SuppressCompany, Value: "acme"}},
+		},
+		{
+			name:      "duplicates count once",
+			csv:       "url\nhttps://www.linkedin.com/in/jane-doe/\nhttps://linkedin.com/in/Jane-Doe?trk=search\nhttps://www.linkedin.com/in/jane-doe/en\n",
+			wantAdded: 1, wantTotal: 3,
+			wantStored: []Suppression{{Kind: SuppressURL, Value: "https://www.linkedin.com/in/jane-doe"}},
+		},
+		{
+			name:      "non-profile URLs are skipped",
+			csv:       "url,name\nhttps://www.linkedin.com/company/acme/,\nhttps://www.linkedin.com/in/,\nnot a url,Jane Doe\nhttps://example.com/in/jane-doe,\n",
+			wantAdded: 1, wantTotal: 1,
+			wantStored: []Suppression{{Kind: SuppressName, Value: "jane doe"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			added, total, err := ImportSuppressionCSV(db, writeCSV(t, tt.csv))
+			if err != nil {
+				t.Fatalf("ImportSuppressionCSV: %v", err)
+			}
+			if added != tt.wantAdded || total != tt.wantTotal {
+				t.Errorf("added %d of %d, want %d of %d", added, total, tt.wantAdded, tt.wantTotal)
+			}
+			stored, err := GetSuppressions(db)
+			if err != nil {
+				t.Fatalf("GetSuppressions: %v", err)
+			}
+			if !slices.Equal(stored, tt.wantStored) {
+				t.Errorf("stored %+v, want %+v", stored, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestImportSuppressionCSVTwice(t *testing.T) {
+	db := openTestDB(t)
+	path := writeCSV(t, "url,name\nhttps://www.linkedin.com/in/jane-doe,John Roe\n")
+	if _, _, err := ImportSuppressionCSV(db, path); err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+
+	added, total, err := ImportSuppressionCSV(db, path)
+	if err != nil || added != 0 || total != 2 {
+		t.Errorf("second import added %d of %d (%v), want 0 of 2", added, total, err)
+	}
+}
+
+func TestImportSuppressionCSVErrors(t *testing.T) {
+	db := openTestDB(t)
+	if _, _, err := ImportSuppressionCSV(db, filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("import of a missing file: no error")
+	}
+	if _, _, err := ImportSuppressionCSV(db, writeCSV(t, "url\n\"unterminated\n")); err == nil {
+		t.Error("import of malformed CSV: no error")
+	}
+}
+
+func TestApplySuppressions(t *testing.T) {
+	db := openTestDB(t)
+	const base = "https://www.linkedin.com/in/"
+	addProfile(t, db, Profile{URL: base + "client"})
+	addProfile(t, db, Profile{URL: base + "coworker", ProfileDetails: ProfileDetails{Company: "ACME Corp"}})
+	addProfile(t, db, Profile{URL: base + "stranger", ProfileDetails: ProfileDetails{Company: "Globex"}})
+	addProfile(t, db, Profile{URL: base + "opted-out"})
+	if err := MarkOptedOut(db, base+"opted-out", "test"); err != nil {
+		t.Fatalf("MarkOptedOut: %v", err)
+	}
+	if _, _, err := ImportSuppressionCSV(db, writeCSV(t, "url,company\n"+base+"client/,\n,acme corp\n")); err != nil {
+		t.Fatalf("ImportSuppressionCSV: %v", err)
+	}
+
+	n, err := ApplySuppressions(db)
+	if err != nil || n != 2 {
+		t.Errorf("ApplySuppressions = %d, %v; want 2", n, err)
+	}
+	tests := []struct {
+		slug string
+		want ProfileStatus
+	}{
+		{"client", StatusSuppressed},
+		{"coworker", StatusSuppressed},
+		{"stranger", StatusFound},
+		{"opted-out", StatusOptedOut},
+	}
+	for _, tt := range tests {
+		if got := statusOf(t, db, base+tt.slug); got != tt.want {
+			t.Errorf("%s: status %s, want %s", tt.slug, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package template renders personalized connection notes and messages.
+//
+// Templates use text/template syntax with the fields of Data, for example:
+//
+//	Hi {{.FirstName | default "there"}}{{if .Company}}, I see you're at {{.Company}}{{end}}!
+//
+// The legacy {firstName} placeholder is still accepted and means {{.FirstName | default "there"}}.
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+// ConnectNoteLimit is the maximum length of a connection note accepted by the site.
+const ConnectNoteLimit = 300
+
+// Data is what a template can reference about the recipient.
+type Data struct {
+	FirstName string
+	LastName  string
+	FullName  string
+	Company   string
+	Headline  string
+	Location  string
+}
+
+// legacyPlaceholders maps the old single-brace placeholders to their template equivalent.
+var legacyPlaceholders = strings.NewReplacer(
+	"{firstName}", `{{.FirstName | default "there"}}`,
+)
+
+// funcs are the functions available inside templates.
+var funcs = texttemplate.FuncMap{
+	// default returns def when value is empty: {{.Company | default "your company"}}
+	"default": func(def, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return def
+		}
+		return value
+	},
+}
+
+// sample is a deliberately long profile used to check rendered lengths at load time.
+var sample = Data{
+	FirstName: "Alexandra",
+	LastName:  "Fernandez-Williams",
+	FullName:  "Alexandra Fernandez-Williams",
+	Company:   "International Business Machines",
+	Headline:  "Senior Software Engineer at International Business Machines | Distributed Systems",
+	Location:  "San Francisco Bay Area",
+}
+
+// Template is a parsed and validated message template.
+type Template struct {
+	name   string
+	source string
+	tmpl   *texttemplate.Template
+}
+
+// Fields returns the names a template may reference, sorted.
+func Fields() []string {
+	t := reflect.TypeOf(Data{})
+	names := make([]string, t.NumField())
+	for i := range names {
+		names[i] = t.Field(i).Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse parses source and rejects references to fields that are not in Data,
+// including fields inside branches that a given profile would not reach.
+func Parse(name, source string) (*Template, error) {
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Option("missingkey=error").Parse(legacyPlaceholders.Replace(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	known := make(map[string]bool)
+	for _, f := range Fields() {
+		known[f] = true
+	}
+	var unknown []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walk(t.Tree.Root, func(field string) {
+				if !known[field] {
+					unknown = append(unknown, "."+field)
+				}
+			})
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%s: unknown variable(s) %s (available: .%s)",
+			name, strings.Join(unknown, ", "), strings.Join(Fields(), ", ."))
+	}
+
+	return &Template{name: name, source: source, tmpl: tmpl}, nil
+}
+
+// walk calls visit with the first identifier of every field reference under node
+func walk(node parse.Node, visit func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walk(child, visit)
+		}
+	case *parse.ActionNode:
+		walk(n.Pipe, visit)
+	case *parse.IfNode:
+		walk(n.Pipe, visit)
+		walk(n.List, visit)
+		walk(n.ElseList, visit)
+	case *parse.RangeNode:
+		walk(n.Pipe, visit)
+		walk(n.List, visit)
+		walk(n.ElseList, visit)
+	case *parse.WithNode:
+		walk(n.Pipe, visit)
+		walk(n.List, visit)
+		walk(n.ElseList, visit)
+	case *parse.TemplateNode:
+		walk(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walk(cmd, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walk(arg, visit)
+		}
+	case *parse.ChainNode:
+		walk(n.Node, visit)
+	case *parse.FieldNode:
+		visit(n.Ident[0])
+	}
+}
+
+// Name returns the name the template was parsed with.
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Source returns the template text as configured.
+func (t *Template) Source() string {
+	return t.source
+}
+
+// Render fills the template for one recipient. Surrounding whitespace is trimmed.
+func (t *Template) Render(d Data) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("%s: %w", t.name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// CheckLength renders the template for a long sample profile and for a profile with no
+// details at all, and fails if either exceeds limit characters.
+func (t *Template) CheckLength(limit int) error {
+	for _, d := range []Data{sample, {}} {
+		text, err := t.Render(d)
+		if err != nil {
+			return err
+		}
+		if n := len([]rune(text)); n > limit {
+			return fmt.Errorf("%s: renders to %d characters for a sample profile, over the %d character limit", t.name, n, limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	jane := Data{FirstName: "Jane", LastName: "Doe", FullName: "Jane Doe", Company: "Acme"}
+
+	tests := []struct {
+		name   string
+		source string
+		data   Data
+		want   string
+	}{
+		{"plain field", "Hi {{.FirstName}}!", jane, "Hi Jane!"},
+		{"default used", `Hi {{.FirstName | default "there"}}!`, Data{}, "Hi there!"},
+		{"default skipped", `Hi {{.FirstName | default "there"}}!`, jane, "Hi Jane!"},
+		{"blank counts as empty", `Hi {{.FirstName | default "there"}}!`, Data{FirstName: "  "}, "Hi there!"},
+		{"branch taken", "Hi{{if .Company}} from {{.Company}}{{end}}", jane, "Hi from Acme"},
+		{"branch skipped", "Hi{{if .Company}} from {{.Company}}{{end}}", Data{}, "Hi"},
+		{"legacy placeholder", "Hi {firstName}, let's connect", jane, "Hi Jane, let's connect"},
+		{"legacy placeholder default", "Hi {firstName}, let's connect", Data{}, "Hi there, let's connect"},
+		{"surrounding space trimmed", "\n  Hi {{.FullName}}  \n", jane, "Hi Jane Doe"},
+	}
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.name, tt.source)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tt.name, err)
+			continue
+		}
+		got, err := tmpl.Render(tt.data)
+		if err != nil {
+			t.Errorf("%s: Render: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Render = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"unknown field", "Hi {{.Nickname}}", "unknown variable(s) .Nickname"},
+		{"unknown field in untaken branch", "Hi{{if .Company}} {{.Employer}}{{end}}", ".Employer"},
+		{"unknown function", "Hi {{.FirstName | shout}}", `function "shout" not defined`},
+		{"syntax error", "Hi {{.FirstName", "unclosed action"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.name, tt.source)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Parse error = %v, want it to contain %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{"short note", `Hi {{.FirstName | default "there"}}, I'd love to connect!`, false},
+		{"long sample profile", "{{.Headline}} {{.Headline}} {{.Headline}} {{.Headline}}", true},
+		{"long for an empty profile", `{{.Company | default "` + strings.Repeat("x", 301) + `"}}`, true},
+	}
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.name, tt.source)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tt.name, err)
+		}
+		if err := tmpl.CheckLength(ConnectNoteLimit); (err != nil) != tt.wantErr {
+			t.Errorf("%s: CheckLength error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}